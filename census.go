@@ -0,0 +1,394 @@
+package golife
+
+import (
+	"fmt"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+const (
+	max_census_period = 64
+	wechsler_chars    = "0123456789abcdefghijklmnopqrstuvwxyz"
+)
+
+type ObjectType int
+
+const (
+	Unclassified ObjectType = iota
+	StillLife
+	Oscillator
+	Spaceship
+)
+
+func (t ObjectType) String() string {
+	switch t {
+	case StillLife:
+		return "still life"
+	case Oscillator:
+		return "oscillator"
+	case Spaceship:
+		return "spaceship"
+	default:
+		return "unclassified"
+	}
+}
+
+// Classification describes how an isolated object behaves once it is run
+// on its own.  Dx and Dy are the displacement over one full Period.
+type Classification struct {
+	Type    ObjectType
+	Period  int
+	Dx, Dy  Coord
+	Apgcode string
+}
+
+// Velocity describes how fast and which way a spaceship moves, in the usual
+// notation, e.g. "c/4 diagonal" for the glider or "c/2 orthogonal" for the
+// LWSS.  It's empty for anything that isn't a spaceship.
+func (class Classification) Velocity() string {
+	if class.Type != Spaceship {
+		return ""
+	}
+	dx, dy := class.Dx, class.Dy
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+
+	direction := "oblique"
+	if dx == 0 || dy == 0 {
+		direction = "orthogonal"
+	} else if dx == dy {
+		direction = "diagonal"
+	}
+
+	speed := max(dx, dy)
+	period := Coord(class.Period)
+	divisor := gcd(speed, period)
+	speed, period = speed/divisor, period/divisor
+	if direction == "oblique" {
+		return fmt.Sprintf("(%d,%d)c/%d %s", dx, dy, class.Period, direction)
+	}
+	if speed == 1 {
+		return fmt.Sprintf("c/%d %s", period, direction)
+	}
+	return fmt.Sprintf("%dc/%d %s", speed, period, direction)
+}
+
+func gcd(a, b Coord) Coord {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Objects separates a population into clusters of cells that are close
+// enough to interact, i.e. within two cells of each other.  Pseudo objects
+// made of separate pieces that happen to sit that close are kept together,
+// SplitObjects separates them.
+func (pop Population) Objects() []Population {
+	return pop.clusters(2)
+}
+
+// islands separates a population into groups of touching cells.
+func (pop Population) islands() []Population {
+	return pop.clusters(1)
+}
+
+func (pop Population) clusters(reach Coord) []Population {
+	objects := make([]Population, 0)
+	seen := make(Population, len(pop))
+
+	for start, present := range pop {
+		if !present || seen[start] {
+			continue
+		}
+		object := make(Population)
+		queue := []Cell{start}
+		seen[start] = true
+		for len(queue) > 0 {
+			cell := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			object[cell] = true
+			for dy := -reach; dy <= reach; dy++ {
+				for dx := -reach; dx <= reach; dx++ {
+					neighbor := Cell{cell.X + dx, cell.Y + dy}
+					if pop[neighbor] && !seen[neighbor] {
+						seen[neighbor] = true
+						queue = append(queue, neighbor)
+					}
+				}
+			}
+		}
+		objects = append(objects, object)
+	}
+
+	return objects
+}
+
+// Objects with more islands than this aren't split, as finding the parts
+// takes time exponential in the number of islands.
+const max_split_islands = 12
+
+// SplitObjects is like Objects, except that pseudo still lives and pseudo
+// oscillators are split into the smallest parts that behave the same on
+// their own, the way apgsearch does, so a bi-block is two blocks.
+func (pop Population) SplitObjects() []Population {
+	objects := make([]Population, 0)
+	for _, object := range pop.Objects() {
+		objects = append(objects, object.split()...)
+	}
+	return objects
+}
+
+func (object Population) split() []Population {
+	class := object.Classify(max_census_period)
+	if class.Type != StillLife && class.Type != Oscillator {
+		return []Population{object}
+	}
+	islands := object.islands()
+	if len(islands) < 2 || len(islands) > max_split_islands {
+		return []Population{object}
+	}
+	// Sorted so ties between equally small parts always go the same way.
+	slices.SortFunc(islands, func(a, b Population) int {
+		cells := CellList{firstCell(a), firstCell(b)}
+		if cells.Less(0, 1) {
+			return -1
+		}
+		return 1
+	})
+
+	parts := make([]Population, 0)
+	for len(islands) > 1 {
+		part, rest := splitOff(islands, class.Period)
+		if part == nil {
+			break
+		}
+		parts = append(parts, part)
+		islands = rest
+	}
+	return append(parts, unionOf(islands))
+}
+
+// splitOff finds the smallest group of islands that evolves independently
+// of the others, returning it merged into one population along with the
+// islands left over, or nil if there isn't one.
+func splitOff(islands []Population, period int) (Population, []Population) {
+	n := len(islands)
+	for size := 1; size <= n/2; size++ {
+		for mask := 1; mask < 1<<n-1; mask++ {
+			if bits.OnesCount(uint(mask)) != size {
+				continue
+			}
+			var in, out []Population
+			for i, island := range islands {
+				if mask&(1<<i) != 0 {
+					in = append(in, island)
+				} else {
+					out = append(out, island)
+				}
+			}
+			part := unionOf(in)
+			if independent(part, unionOf(out), period) {
+				return part, out
+			}
+		}
+	}
+	return nil, islands
+}
+
+// independent reports whether part and rest, run separately for a period,
+// each come back to where they started and together match the two run as
+// one.
+func independent(part, rest Population, period int) bool {
+	start := part
+	whole := unionOf([]Population{part, rest})
+	for range period {
+		part, rest, whole = part.Step(), rest.Step(), whole.Step()
+		if part.Size()+rest.Size() != whole.Size() {
+			return false
+		}
+		for cell := range part {
+			if rest[cell] || !whole[cell] {
+				return false
+			}
+		}
+		for cell := range rest {
+			if !whole[cell] {
+				return false
+			}
+		}
+	}
+	return part.Size() == start.Size() && start.sameShape(part, 0, 0)
+}
+
+func unionOf(pops []Population) Population {
+	union := make(Population)
+	for _, pop := range pops {
+		for cell, present := range pop {
+			if present {
+				union[cell] = true
+			}
+		}
+	}
+	return union
+}
+
+func firstCell(pop Population) Cell {
+	for cell := range pop.All() {
+		return cell
+	}
+	return Cell{}
+}
+
+// Classify runs the population in isolation for up to maxPeriod generations
+// looking for the first generation where it repeats, possibly translated.
+func (pop Population) Classify(maxPeriod int) Classification {
+	var result Classification
+	if pop.Size() == 0 {
+		return result
+	}
+
+	start, _ := pop.BoundingBox()
+	phases := []Population{pop}
+	current := pop
+	for period := 1; period <= maxPeriod; period++ {
+		current = current.Step()
+		if current.Size() == 0 {
+			return result
+		}
+		min_cell, _ := current.BoundingBox()
+		dx, dy := min_cell.X-start.X, min_cell.Y-start.Y
+		if current.Size() == pop.Size() && pop.sameShape(current, dx, dy) {
+			result.Period = period
+			result.Dx = dx
+			result.Dy = dy
+			switch {
+			case dx != 0 || dy != 0:
+				result.Type = Spaceship
+				result.Apgcode = fmt.Sprintf("xq%d_%s", period, canonicalWechsler(phases))
+			case period == 1:
+				result.Type = StillLife
+				result.Apgcode = fmt.Sprintf("xs%d_%s", pop.Size(), canonicalWechsler(phases))
+			default:
+				result.Type = Oscillator
+				result.Apgcode = fmt.Sprintf("xp%d_%s", period, canonicalWechsler(phases))
+			}
+			return result
+		}
+		phases = append(phases, current)
+	}
+
+	return result
+}
+
+// Apgcode returns the apgsearch style identifier for the population, or an
+// empty string if it can't be classified within the census period limit.
+func (pop Population) Apgcode() string {
+	return pop.Classify(max_census_period).Apgcode
+}
+
+// Census counts the objects in a settled population by apgcode, with pseudo
+// objects split as by SplitObjects.  Objects that can't be classified are
+// returned separately.
+func Census(pop Population) (map[string]int, []Population) {
+	counts := make(map[string]int)
+	unclassified := make([]Population, 0)
+
+	for _, object := range pop.SplitObjects() {
+		code := object.Apgcode()
+		if code == "" {
+			unclassified = append(unclassified, object)
+		} else {
+			counts[code] += 1
+		}
+	}
+
+	return counts, unclassified
+}
+
+func (pop Population) sameShape(other Population, dx, dy Coord) bool {
+	for cell, present := range pop {
+		if present && !other[Cell{cell.X + dx, cell.Y + dy}] {
+			return false
+		}
+	}
+	return true
+}
+
+var orientations = [8][4]Coord{
+	{1, 0, 0, 1},
+	{0, 1, 1, 0},
+	{-1, 0, 0, 1},
+	{0, -1, 1, 0},
+	{1, 0, 0, -1},
+	{0, 1, -1, 0},
+	{-1, 0, 0, -1},
+	{0, -1, -1, 0},
+}
+
+// canonicalWechsler picks the shortest, then lexically smallest, extended
+// Wechsler encoding across all phases and orientations.
+func canonicalWechsler(phases []Population) string {
+	best := ""
+	for _, phase := range phases {
+		for _, o := range orientations {
+			transformed := make(Population, len(phase))
+			for cell := range phase {
+				transformed[Cell{o[0]*cell.X + o[1]*cell.Y, o[2]*cell.X + o[3]*cell.Y}] = true
+			}
+			code := wechsler(transformed)
+			if best == "" || len(code) < len(best) || len(code) == len(best) && code < best {
+				best = code
+			}
+		}
+	}
+	return best
+}
+
+func wechsler(pop Population) string {
+	var code strings.Builder
+	min_cell, max_cell := pop.BoundingBox()
+
+	for strip := min_cell.Y; strip <= max_cell.Y; strip += 5 {
+		if strip != min_cell.Y {
+			code.WriteString("z")
+		}
+		zeroes := 0
+		for x := min_cell.X; x <= max_cell.X; x++ {
+			value := 0
+			for row := Coord(0); row < 5; row++ {
+				if pop[Cell{x, strip + row}] {
+					value |= 1 << row
+				}
+			}
+			if value == 0 {
+				zeroes += 1
+				continue
+			}
+			for zeroes > 39 {
+				code.WriteString("yz")
+				zeroes -= 39
+			}
+			switch {
+			case zeroes == 1:
+				code.WriteString("0")
+			case zeroes == 2:
+				code.WriteString("w")
+			case zeroes == 3:
+				code.WriteString("x")
+			case zeroes > 3:
+				code.WriteString("y")
+				code.WriteByte(wechsler_chars[zeroes-4])
+			}
+			zeroes = 0
+			code.WriteByte(wechsler_chars[value])
+		}
+	}
+
+	return code.String()
+}
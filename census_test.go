@@ -0,0 +1,87 @@
+package golife_test
+
+import (
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func popFromCells(cells golife.CellList) golife.Population {
+	pop := make(golife.Population)
+	pop.Add(cells)
+	return pop
+}
+
+func TestApgcode(t *testing.T) {
+	cases := []struct {
+		name  string
+		cells golife.CellList
+		code  string
+	}{
+		{"block", golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}}, "xs4_33"},
+		{"beehive", golife.CellList{{1, 0}, {2, 0}, {0, 1}, {3, 1}, {1, 2}, {2, 2}}, "xs6_696"},
+		{"blinker", testPattern, "xp2_7"},
+		{"glider", golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}, "xq4_153"},
+		{"lwss", golife.CellList{{1, 0}, {4, 0}, {0, 1}, {0, 2}, {4, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}}, "xq4_6frc"},
+	}
+
+	for _, c := range cases {
+		code := popFromCells(c.cells).Apgcode()
+		if code != c.code {
+			t.Errorf("%s: expected apgcode %s, got %s", c.name, c.code, code)
+		}
+	}
+}
+
+func TestClassifySpaceship(t *testing.T) {
+	glider := popFromCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	class := glider.Classify(10)
+	if class.Type != golife.Spaceship || class.Period != 4 || class.Dx != 1 || class.Dy != 1 {
+		t.Errorf("Glider misclassified: %+v", class)
+	}
+	if velocity := class.Velocity(); velocity != "c/4 diagonal" {
+		t.Errorf("Expected glider velocity c/4 diagonal, got %q", velocity)
+	}
+
+	lwss := popFromCells(golife.CellList{{1, 0}, {4, 0}, {0, 1}, {0, 2}, {4, 2}, {0, 3}, {1, 3}, {2, 3}, {3, 3}})
+	if velocity := lwss.Classify(10).Velocity(); velocity != "c/2 orthogonal" {
+		t.Errorf("Expected LWSS velocity c/2 orthogonal, got %q", velocity)
+	}
+}
+
+func TestCensus(t *testing.T) {
+	pop := make(golife.Population)
+	pop.Add(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}})
+	pop.Add(golife.CellList{{10, 0}, {11, 0}, {10, 1}, {11, 1}})
+	pop.Add(golife.CellList{{20, 0}, {21, 0}, {22, 0}})
+	pop.Add(golife.CellList{{30, 30}})
+
+	counts, unclassified := golife.Census(pop)
+	if counts["xs4_33"] != 2 || counts["xp2_7"] != 1 || len(counts) != 2 {
+		t.Errorf("Unexpected census %v", counts)
+	}
+	if len(unclassified) != 1 || unclassified[0].Size() != 1 {
+		t.Errorf("Expected the lone cell to be unclassified, got %v", unclassified)
+	}
+}
+
+func TestCensusSplitsPseudoObjects(t *testing.T) {
+	// A bi-block, two blocks a row apart, and a block and blinker sitting
+	// close enough to look like one object.
+	pop := popFromCells(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {0, 3}, {1, 3}, {0, 4}, {1, 4}})
+	pop.Add(golife.CellList{{20, 0}, {21, 0}, {20, 1}, {21, 1}, {23, 3}, {24, 3}, {25, 3}})
+
+	if objects := pop.Objects(); len(objects) != 2 {
+		t.Fatalf("Expected two pseudo objects to start with, got %d", len(objects))
+	}
+	counts, unclassified := golife.Census(pop)
+	if counts["xs4_33"] != 3 || counts["xp2_7"] != 1 || len(counts) != 2 || len(unclassified) != 0 {
+		t.Errorf("Expected three blocks and a blinker, got %v and %v", counts, unclassified)
+	}
+
+	// A still life whose cells all touch is left whole.
+	pop = popFromCells(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {2, 1}, {1, 2}, {3, 2}, {2, 3}, {4, 3}, {3, 4}, {4, 4}})
+	if objects := pop.SplitObjects(); len(objects) != 1 {
+		t.Errorf("Expected one object, got %d", len(objects))
+	}
+}
@@ -0,0 +1,189 @@
+package golife
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	checkpoint_prefix = "checkpoint-"
+	checkpoint_suffix = ".lifesnap"
+)
+
+// checkpointer writes snapshots of the game to a directory as it runs.
+// Files are numbered in the order they were written rather than by
+// generation, so the newest is still the last one after going backward.
+type checkpointer struct {
+	dir            string
+	generations    int
+	interval       time.Duration
+	keep           int
+	sequence       int
+	lastGeneration int
+	lastTime       time.Time
+	err            error
+}
+
+// SetCheckpoints makes Next save a snapshot of the game to dir every
+// generations generations or every interval of time, whichever comes
+// first, keeping the newest keep of them (or all of them if keep is 0 or
+// less).  A zero for generations or interval leaves that trigger off, and
+// an empty dir turns checkpoints off.  Each checkpoint is written to a
+// temporary file that is renamed into place, so a crash can't leave a
+// partly written one behind.
+func (game *Game) SetCheckpoints(dir string, generations int, interval time.Duration, keep int) error {
+	if dir == "" {
+		game.checkpoints = nil
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	sequences, err := checkpointSequences(dir)
+	if err != nil {
+		return err
+	}
+
+	var cp checkpointer
+	cp.dir = dir
+	cp.generations = generations
+	cp.interval = interval
+	cp.keep = keep
+	if len(sequences) > 0 {
+		cp.sequence = sequences[len(sequences)-1]
+	}
+	cp.lastGeneration = game.Generation
+	cp.lastTime = time.Now()
+	game.checkpoints = &cp
+	return nil
+}
+
+// CheckpointError returns the error from the most recent checkpoint
+// written by Next, or nil if it succeeded.
+func (game *Game) CheckpointError() error {
+	if game.checkpoints == nil {
+		return nil
+	}
+	return game.checkpoints.err
+}
+
+func (game *Game) autoCheckpoint() {
+	cp := game.checkpoints
+	if cp == nil {
+		return
+	}
+	due := cp.generations > 0 && game.Generation-cp.lastGeneration >= cp.generations
+	due = due || cp.interval > 0 && time.Since(cp.lastTime) >= cp.interval
+	if due {
+		cp.err = game.Checkpoint()
+	}
+}
+
+// Checkpoint writes a checkpoint straight away, e.g. before shutting down.
+func (game *Game) Checkpoint() error {
+	cp := game.checkpoints
+	if cp == nil {
+		return errors.New("Checkpoints are not enabled")
+	}
+	cp.lastGeneration = game.Generation
+	cp.lastTime = time.Now()
+
+	tmpfile, err := os.CreateTemp(cp.dir, ".checkpoint-*.tmp")
+	if err != nil {
+		return err
+	}
+	err = game.WriteSnapshot(tmpfile)
+	if err == nil {
+		err = tmpfile.Sync()
+	}
+	if closeErr := tmpfile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpfile.Name(), checkpointPath(cp.dir, cp.sequence+1))
+	}
+	if err != nil {
+		os.Remove(tmpfile.Name())
+		return err
+	}
+	cp.sequence += 1
+	return cp.prune()
+}
+
+func (cp *checkpointer) prune() error {
+	if cp.keep <= 0 {
+		return nil
+	}
+	sequences, err := checkpointSequences(cp.dir)
+	if err != nil {
+		return err
+	}
+	for len(sequences) > cp.keep {
+		if err := os.Remove(checkpointPath(cp.dir, sequences[0])); err != nil {
+			return err
+		}
+		sequences = sequences[1:]
+	}
+	return nil
+}
+
+func checkpointPath(dir string, sequence int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%08d%s", checkpoint_prefix, sequence, checkpoint_suffix))
+}
+
+// checkpointSequences lists the sequence numbers of the checkpoints in dir,
+// oldest first.
+func checkpointSequences(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	sequences := make([]int, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, checkpoint_prefix) || !strings.HasSuffix(name, checkpoint_suffix) {
+			continue
+		}
+		sequence, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, checkpoint_prefix), checkpoint_suffix))
+		if err == nil {
+			sequences = append(sequences, sequence)
+		}
+	}
+	slices.Sort(sequences)
+	return sequences, nil
+}
+
+// ErrNoCheckpoint is returned by Resume when there are no checkpoints at
+// all, as opposed to only damaged ones.
+var ErrNoCheckpoint = errors.New("No checkpoints")
+
+// Resume loads the newest checkpoint in dir that reads back cleanly,
+// skipping any that are damaged.  Checkpointing isn't turned back on;
+// call SetCheckpoints on the game to carry on writing them.
+func Resume(dir string) (*Game, error) {
+	sequences, err := checkpointSequences(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(sequences) == 0 {
+		return nil, ErrNoCheckpoint
+	}
+	for i := len(sequences) - 1; i >= 0; i-- {
+		file, err := os.Open(checkpointPath(dir, sequences[i]))
+		if err != nil {
+			continue
+		}
+		game, err := ReadSnapshot(file)
+		file.Close()
+		if err == nil {
+			return game, nil
+		}
+	}
+	return nil, errors.New("No usable checkpoint in " + dir)
+}
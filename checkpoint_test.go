@@ -0,0 +1,86 @@
+package golife_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestCheckpoints(t *testing.T) {
+	dir := t.TempDir()
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	game.SetHistorySize(10)
+	if err := game.SetCheckpoints(dir, 10, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 55 {
+		game.Next()
+		if err := game.CheckpointError(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 3 {
+		t.Errorf("Expected 3 checkpoints to be kept, found %v", files)
+	}
+
+	resumed, err := golife.Resume(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Generation != 50 {
+		t.Errorf("Expected to resume at generation 50, got %d", resumed.Generation)
+	}
+	if len(resumed.History) != 10 {
+		t.Errorf("Expected the history to be resumed, got %d generations", len(resumed.History))
+	}
+	for resumed.Generation < game.Generation {
+		resumed.Next()
+	}
+	if match, errmsg := samePop(game.Population, resumed.Population); !match {
+		t.Error("Resumed game went a different way: ", errmsg)
+	}
+}
+
+func TestResumeSkipsDamagedCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	if err := game.SetCheckpoints(dir, 5, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	for range 10 {
+		game.Next()
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 checkpoints, found %v", files)
+	}
+	if err := os.WriteFile(files[1], []byte("GLSN\x01garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resumed, err := golife.Resume(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Generation != 5 {
+		t.Errorf("Expected to fall back to generation 5, got %d", resumed.Generation)
+	}
+
+	if _, err := golife.Resume(t.TempDir()); err != golife.ErrNoCheckpoint {
+		t.Errorf("Expected ErrNoCheckpoint from an empty directory, got %v", err)
+	}
+	if err := os.WriteFile(files[0], []byte("GLSN\x01garbage"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := golife.Resume(dir); err == nil || err == golife.ErrNoCheckpoint {
+		t.Errorf("Expected an error for only damaged checkpoints, got %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func convertCommand(args []string) {
+	flags := flag.NewFlagSet("convert", flag.ExitOnError)
+	fromPtr := flags.String("from", "", "Format of the input, default from the file name or rle for stdin")
+	toPtr := flags.String("to", "", "Format of the output, default from the file name or rle for stdout")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s convert [options] <in> <out>\n\nUse - for stdin or stdout.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() != 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	in, out := flags.Arg(0), flags.Arg(1)
+
+	inFormat := formatOf(in, *fromPtr)
+	outFormat := formatOf(out, *toPtr)
+
+	infile := openInput(in)
+	defer infile.Close()
+	outfile := createOutput(out)
+	check(golife.Convert(infile, inFormat, outfile, outFormat))
+	check(outfile.Close())
+}
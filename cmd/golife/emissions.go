@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+type emissionInfo struct {
+	Generation int          `json:"generation"`
+	Name       string       `json:"name"`
+	Apgcode    string       `json:"apgcode"`
+	Velocity   string       `json:"velocity"`
+	Direction  string       `json:"direction"`
+	Lane       golife.Coord `json:"lane"`
+	X          golife.Coord `json:"x"`
+	Y          golife.Coord `json:"y"`
+}
+
+type emissionsInfo struct {
+	File      string         `json:"file"`
+	Period    int            `json:"period"`
+	Emissions []emissionInfo `json:"emissions"`
+}
+
+func emissionsCommand(args []string) {
+	flags := flag.NewFlagSet("emissions", flag.ExitOnError)
+	gensPtr := flags.Int("gens", 500, "Number of generations to watch the pattern for")
+	jsonPtr := flags.Bool("json", false, "Print a JSON object per pattern instead of text")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s emissions [options] <pattern>...\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for i, path := range flags.Args() {
+		game, err := golife.Load(path)
+		check(err)
+		report := golife.FindEmissions(game.Population, *gensPtr)
+
+		info := emissionsInfo{path, report.Period, make([]emissionInfo, len(report.Emissions))}
+		for j, emission := range report.Emissions {
+			info.Emissions[j] = emissionInfo{emission.Generation, emission.Name,
+				emission.Classification.Apgcode, emission.Classification.Velocity(),
+				emission.Direction, emission.Lane, emission.Position.X, emission.Position.Y}
+		}
+
+		if *jsonPtr {
+			check(encoder.Encode(info))
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println("File:  ", path)
+		if info.Period > 0 {
+			fmt.Println("Period:", info.Period)
+		} else {
+			fmt.Println("Period: unknown")
+		}
+		for _, emission := range info.Emissions {
+			fmt.Printf("  generation %-6d %-8s %-4s lane %-5d at %d,%d (%s)\n", emission.Generation,
+				emission.Name, emission.Direction, emission.Lane, emission.X, emission.Y, emission.Velocity)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+// formatOf picks the format for a file: the one given with a flag if any,
+// otherwise the one its name suggests, with rle for stdin and stdout.
+func formatOf(path, flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path == "-" || path == "" {
+		return "rle"
+	}
+	format := golife.FormatFor(path)
+	if format == "" {
+		fmt.Fprintf(os.Stderr, "Can't tell the format of %q from its name\n", path)
+		os.Exit(2)
+	}
+	return format
+}
+
+func openInput(path string) *os.File {
+	if path == "-" || path == "" {
+		return os.Stdin
+	}
+	file, err := os.Open(path)
+	check(err)
+	return file
+}
+
+func createOutput(path string) *os.File {
+	if path == "-" || path == "" {
+		return os.Stdout
+	}
+	file, err := os.Create(path)
+	check(err)
+	return file
+}
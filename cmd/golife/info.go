@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+type boundingBox struct {
+	MinX   golife.Coord `json:"minX"`
+	MinY   golife.Coord `json:"minY"`
+	MaxX   golife.Coord `json:"maxX"`
+	MaxY   golife.Coord `json:"maxY"`
+	Width  golife.Coord `json:"width"`
+	Height golife.Coord `json:"height"`
+}
+
+type classificationInfo struct {
+	Type     string `json:"type"`
+	Period   int    `json:"period,omitempty"`
+	Dx       int64  `json:"dx,omitempty"`
+	Dy       int64  `json:"dy,omitempty"`
+	Velocity string `json:"velocity,omitempty"`
+	Apgcode  string `json:"apgcode,omitempty"`
+}
+
+type patternInfo struct {
+	File           string              `json:"file"`
+	Name           string              `json:"name,omitempty"`
+	Author         string              `json:"author,omitempty"`
+	Comments       []string            `json:"comments,omitempty"`
+	Rule           string              `json:"rule"`
+	Population     int                 `json:"population"`
+	BoundingBox    *boundingBox        `json:"boundingBox,omitempty"`
+	Classification *classificationInfo `json:"classification,omitempty"`
+	Symmetry       golife.Symmetry     `json:"symmetry,omitempty"`
+}
+
+func infoCommand(args []string) {
+	flags := flag.NewFlagSet("info", flag.ExitOnError)
+	classifyPtr := flags.Bool("classify", false, "Run the pattern to classify it and work out its apgcode")
+	periodPtr := flags.Int("max-period", 64, "Longest period looked for by -classify")
+	symmetryPtr := flags.Bool("symmetry", false, "Report the symmetry of the pattern")
+	jsonPtr := flags.Bool("json", false, "Print a JSON object per pattern instead of text")
+	rulePtr := flags.String("rule", "", "Rule to report, in place of the one in the pattern's header")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s info [options] <pattern>...\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	for i, path := range flags.Args() {
+		game, err := golife.Load(path)
+		check(err)
+
+		info := patternInfo{
+			File:       path,
+			Name:       game.Name,
+			Author:     game.Author,
+			Comments:   game.Comments,
+			Rule:       *rulePtr,
+			Population: game.Size(),
+		}
+		if info.Rule == "" {
+			info.Rule = game.Rule
+		}
+		if info.Rule == "" {
+			info.Rule = "B3/S23"
+		}
+		if game.Size() > 0 {
+			min_cell, max_cell := game.Population.BoundingBox()
+			info.BoundingBox = &boundingBox{min_cell.X, min_cell.Y, max_cell.X, max_cell.Y,
+				max_cell.X - min_cell.X + 1, max_cell.Y - min_cell.Y + 1}
+		}
+		if *classifyPtr {
+			class := game.Population.Classify(*periodPtr)
+			info.Classification = &classificationInfo{class.Type.String(), class.Period,
+				int64(class.Dx), int64(class.Dy), class.Velocity(), class.Apgcode}
+		}
+		if *symmetryPtr {
+			info.Symmetry = golife.DetectSymmetry(game.Population)
+		}
+
+		if *jsonPtr {
+			check(encoder.Encode(info))
+			continue
+		}
+		if i > 0 {
+			fmt.Println()
+		}
+		printInfo(info)
+	}
+}
+
+func printInfo(info patternInfo) {
+	fmt.Println("File:          ", info.File)
+	if info.Name != "" {
+		fmt.Println("Name:          ", info.Name)
+	}
+	if info.Author != "" {
+		fmt.Println("Author:        ", info.Author)
+	}
+	fmt.Println("Rule:          ", info.Rule)
+	fmt.Println("Population:    ", info.Population)
+	if box := info.BoundingBox; box != nil {
+		fmt.Printf("Bounding box:   %d,%d -> %d,%d (%dx%d)\n", box.MinX, box.MinY, box.MaxX, box.MaxY, box.Width, box.Height)
+	}
+	if class := info.Classification; class != nil {
+		switch class.Type {
+		case golife.StillLife.String():
+			fmt.Println("Classification: still life")
+		case golife.Oscillator.String():
+			fmt.Printf("Classification: period %d oscillator\n", class.Period)
+		case golife.Spaceship.String():
+			fmt.Printf("Classification: %s spaceship, period %d\n", class.Velocity, class.Period)
+		default:
+			fmt.Println("Classification: none found")
+		}
+		if class.Apgcode != "" {
+			fmt.Println("Apgcode:       ", class.Apgcode)
+		}
+	}
+	if info.Symmetry != "" {
+		fmt.Println("Symmetry:      ", info.Symmetry)
+	}
+	for _, comment := range info.Comments {
+		fmt.Println("Comment:       ", strings.TrimSpace(comment))
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+type command struct {
+	run   func(args []string)
+	usage string
+}
+
+var commands = map[string]command{
+	"convert":   {convertCommand, "convert a pattern from one file format to another"},
+	"emissions": {emissionsCommand, "find the spaceships a gun or puffer sends out and the period it fires at"},
+	"info":      {infoCommand, "describe a pattern: metadata, size, classification and symmetry"},
+	"run":       {runCommand, "run a pattern for a number of generations and write the result"},
+	"soup":      {soupCommand, "search random soups and take a census of what they settle into"},
+}
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%s <command> -h' for the options of a command.\n", os.Args[0])
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, found := commands[os.Args[1]]
+	if !found {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	cmd.run(os.Args[2:])
+}
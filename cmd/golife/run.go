@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func runCommand(args []string) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	gensPtr := flags.Int("gens", 100, "Number of generations to run")
+	untilPtr := flags.Int("until", -1, "Run until this generation instead, e.g. to finish a run carried on with -resume")
+	inPtr := flags.String("in", "-", "Pattern file to read, or - for stdin")
+	inFormatPtr := flags.String("in-format", "", "Format of the input (rle, cells or life), default from the file name or rle")
+	outPtr := flags.String("out", "-", "File to write the result to, or - for stdout")
+	formatPtr := flags.String("format", "", "Format of the output (rle, cells or life), default from the file name or rle")
+	stablePtr := flags.Bool("stop-stable", false, "Stop early once the population dies out or starts repeating")
+	periodPtr := flags.Int("max-period", 64, "Longest period looked for by -stop-stable")
+	statsPtr := flags.Int("stats", 0, "Print population statistics to stderr every this many generations")
+	checkpointPtr := flags.String("checkpoints", "", "Directory to write checkpoints to")
+	everyGensPtr := flags.Int("checkpoint-gens", 0, "Write a checkpoint every this many generations")
+	everyTimePtr := flags.Duration("checkpoint-time", 10*time.Minute, "Write a checkpoint at least this often")
+	keepPtr := flags.Int("keep", 3, "Number of checkpoints to keep, 0 for all")
+	resumePtr := flags.Bool("resume", false, "Carry on from the newest checkpoint instead of reading -in, if there is one")
+	flags.Parse(args)
+
+	inFormat := formatOf(*inPtr, *inFormatPtr)
+	outFormat := formatOf(*outPtr, *formatPtr)
+
+	var game *golife.Game
+	if *resumePtr && *checkpointPtr != "" {
+		var err error
+		game, err = golife.Resume(*checkpointPtr)
+		switch {
+		case err == nil:
+			fmt.Fprintf(os.Stderr, "Resuming at generation %d\n", game.Generation)
+		case errors.Is(err, golife.ErrNoCheckpoint) || errors.Is(err, fs.ErrNotExist):
+			// Nothing to carry on from yet, so start from -in.
+		default:
+			fmt.Fprintf(os.Stderr, "Can't resume from %s: %v\n", *checkpointPtr, err)
+			os.Exit(1)
+		}
+	}
+	if game == nil {
+		infile := openInput(*inPtr)
+		var err error
+		game, err = golife.Read(infile, inFormat)
+		infile.Close()
+		check(err)
+		if *inPtr != "-" {
+			game.Filename = *inPtr
+		}
+	}
+	if *checkpointPtr != "" {
+		check(game.SetCheckpoints(*checkpointPtr, *everyGensPtr, *everyTimePtr, *keepPtr))
+	}
+
+	if *stablePtr {
+		game.SetCycleDetection(*periodPtr)
+	}
+
+	target := game.Generation + *gensPtr
+	if *untilPtr >= 0 {
+		target = *untilPtr
+	}
+
+	stats := *statsPtr > 0
+	if stats {
+		printStats(game)
+	}
+	for game.Generation < target {
+		status := game.Next()
+		check(game.CheckpointError())
+		if stats && game.Generation%*statsPtr == 0 {
+			printStats(game)
+		}
+		if *stablePtr && status != golife.Running {
+			_, period := game.Status()
+			fmt.Fprintf(os.Stderr, "Stopped at generation %d: %s (period %d)\n", game.Generation, status, period)
+			break
+		}
+	}
+	if stats && game.Generation%*statsPtr != 0 {
+		printStats(game)
+	}
+	if *checkpointPtr != "" {
+		check(game.Checkpoint())
+	}
+
+	outfile := createOutput(*outPtr)
+	check(game.Write(outfile, outFormat))
+	check(outfile.Close())
+}
+
+func printStats(game *golife.Game) {
+	min_cell, max_cell := game.Population.BoundingBox()
+	if game.Size() == 0 {
+		fmt.Fprintf(os.Stderr, "generation %d: population 0\n", game.Generation)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "generation %d: population %d, bounded by %d,%d -> %d,%d (%dx%d)\n",
+		game.Generation, game.Size(), min_cell.X, min_cell.Y, max_cell.X, max_cell.Y,
+		max_cell.X-min_cell.X+1, max_cell.Y-min_cell.Y+1)
+}
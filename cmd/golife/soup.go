@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func soupCommand(args []string) {
+	flags := flag.NewFlagSet("soup", flag.ExitOnError)
+	prefixPtr := flags.String("seed", fmt.Sprintf("k_%x_", time.Now().Unix()), "Seed prefix, each soup appends a sequence number")
+	soupsPtr := flags.Int("soups", 1000, "Number of soups to search")
+	flags.Parse(args)
+
+	results := golife.SearchSoups(*prefixPtr, *soupsPtr)
+
+	codes := make([]string, 0, len(results.Counts))
+	for code := range results.Counts {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		if results.Counts[codes[i]] == results.Counts[codes[j]] {
+			return codes[i] < codes[j]
+		}
+		return results.Counts[codes[i]] > results.Counts[codes[j]]
+	})
+
+	fmt.Printf("Searched %d soups with seed prefix %q\n\n", results.Soups, *prefixPtr)
+	for _, code := range codes {
+		fmt.Printf("%-32s %d\n", code, results.Counts[code])
+	}
+
+	if len(results.Rare) > 0 {
+		fmt.Println("\nRare objects:")
+		for _, code := range codes {
+			for _, seed := range results.Rare[code] {
+				fmt.Printf("%-32s %s\n", code, seed)
+			}
+		}
+	}
+	for _, seed := range results.Unclassified {
+		fmt.Println("Unclassified objects in", seed)
+	}
+	for _, seed := range results.Unsettled {
+		fmt.Println("Did not stabilize:", seed)
+	}
+}
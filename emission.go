@@ -0,0 +1,153 @@
+package golife
+
+import (
+	"math"
+)
+
+var spaceshipNames = map[string]string{
+	"xq4_153":     "glider",
+	"xq4_6frc":    "LWSS",
+	"xq4_27dee6":  "MWSS",
+	"xq4_27deee6": "HWSS",
+}
+
+// Emission is a spaceship found leaving a gun or puffer.  Generation is
+// when it was first clear of the rest of the pattern, Position the top left
+// of its bounding box at that point, and Direction a compass direction with
+// north toward negative Y.  Lane tells apart the parallel paths a spaceship
+// could take: the Y coordinate for ships moving east or west, X for north
+// or south, and X-Y or X+Y for diagonal ones, taken at the ship's center
+// averaged over its period so it doesn't depend on the phase.
+type Emission struct {
+	Generation     int
+	Name           string
+	Classification Classification
+	Position       Cell
+	Direction      string
+	Lane           Coord
+}
+
+type EmissionReport struct {
+	Emissions []Emission
+	Period    int
+}
+
+// FindEmissions runs the population for the given number of generations,
+// taking out each spaceship as soon as it has left the rest of the pattern
+// behind, and works out the period of whatever emits them from the
+// intervals between emissions.  The period is 0 if no spaceship was seen
+// twice.
+func FindEmissions(pop Population, generations int) EmissionReport {
+	var report EmissionReport
+	report.Emissions = make([]Emission, 0)
+
+	pop = pop.copy()
+	for gen := 1; gen <= generations; gen++ {
+		pop = pop.Step()
+		for _, object := range escapingObjects(pop) {
+			for cell := range object {
+				delete(pop, cell)
+			}
+			report.Emissions = append(report.Emissions, newEmission(object, gen))
+		}
+	}
+
+	report.Period = emissionPeriod(report.Emissions)
+	return report
+}
+
+func newEmission(object Population, generation int) Emission {
+	var emission Emission
+	class := object.Classify(max_census_period)
+	emission.Generation = generation
+	emission.Classification = class
+	emission.Name = spaceshipNames[class.Apgcode]
+	if emission.Name == "" {
+		emission.Name = class.Apgcode
+	}
+	emission.Position, _ = object.BoundingBox()
+
+	switch {
+	case class.Dy < 0:
+		emission.Direction = "N"
+	case class.Dy > 0:
+		emission.Direction = "S"
+	}
+	switch {
+	case class.Dx < 0:
+		emission.Direction += "W"
+	case class.Dx > 0:
+		emission.Direction += "E"
+	}
+
+	lane := func(cell Cell) Coord {
+		switch {
+		case class.Dx == 0:
+			return cell.X
+		case class.Dy == 0:
+			return cell.Y
+		case class.Dx*class.Dy > 0:
+			return cell.X - cell.Y
+		default:
+			return cell.X + cell.Y
+		}
+	}
+	var sum, count Coord
+	phase := object
+	for range class.Period {
+		for cell := range phase {
+			sum += lane(cell)
+			count += 1
+		}
+		phase = phase.Step()
+	}
+	emission.Lane = Coord(math.Round(float64(sum) / float64(count)))
+	return emission
+}
+
+// emissionPeriod finds the period of a gun from the generations each stream
+// of identical spaceships on the same lane came out at.  Puffers such as
+// rakes move along as they go, putting each ship on a new lane, so when no
+// lane is used twice the streams are taken by direction alone.
+func emissionPeriod(emissions []Emission) int {
+	type stream struct {
+		name, direction string
+		lane            Coord
+	}
+
+	byLane := make(map[stream][]int)
+	byDirection := make(map[stream][]int)
+	for _, emission := range emissions {
+		key := stream{emission.Name, emission.Direction, emission.Lane}
+		byLane[key] = append(byLane[key], emission.Generation)
+		key.lane = 0
+		byDirection[key] = append(byDirection[key], emission.Generation)
+	}
+
+	if period := streamsPeriod(byLane); period > 0 {
+		return period
+	}
+	return streamsPeriod(byDirection)
+}
+
+// streamsPeriod takes the period of each stream to be the greatest common
+// divisor of the intervals between its emissions, and the overall period to
+// be the least common multiple of those.
+func streamsPeriod[K comparable](streams map[K][]int) int {
+	period := 0
+	for _, generations := range streams {
+		streamPeriod := 0
+		for i := 1; i < len(generations); i++ {
+			streamPeriod = int(gcd(Coord(streamPeriod), Coord(generations[i]-generations[i-1])))
+		}
+		if streamPeriod == 0 {
+			continue
+		}
+		if period == 0 {
+			period = streamPeriod
+		} else {
+			period = period / int(gcd(Coord(period), Coord(streamPeriod))) * streamPeriod
+		}
+	}
+	return period
+}
@@ -0,0 +1,45 @@
+package golife_test
+
+import (
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestFindEmissions(t *testing.T) {
+	cases := []struct {
+		file      string
+		name      string
+		direction string
+		period    int
+	}{
+		{"examples/files/Growing/gosper_glider_gun.rle", "glider", "SE", 30},
+		{"examples/files/Other_Guns/period44mwssgun.rle", "MWSS", "S", 44},
+	}
+	for _, c := range cases {
+		game, err := golife.Load(c.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		report := golife.FindEmissions(game.Population, 200)
+		if report.Period != c.period {
+			t.Errorf("Expected period %d for %s, got %d", c.period, c.file, report.Period)
+		}
+		if len(report.Emissions) < 2 {
+			t.Fatalf("Expected several emissions from %s, got %d", c.file, len(report.Emissions))
+		}
+		first := report.Emissions[0]
+		for _, emission := range report.Emissions {
+			if emission.Name != c.name || emission.Direction != c.direction || emission.Lane != first.Lane {
+				t.Errorf("Expected %s going %s on lane %d from %s, got %+v", c.name, c.direction, first.Lane, c.file, emission)
+			}
+		}
+	}
+}
+
+func TestFindEmissionsStillLife(t *testing.T) {
+	block := popFromCells(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}})
+	if report := golife.FindEmissions(block, 50); len(report.Emissions) != 0 || report.Period != 0 {
+		t.Errorf("Found emissions from a block: %+v", report)
+	}
+}
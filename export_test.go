@@ -0,0 +1,17 @@
+package golife
+
+import (
+	"time"
+)
+
+// SetRunnerClock replaces the clock a Runner keeps its pace by.  It must be
+// called before Run.
+func SetRunnerClock(runner *Runner, now func() time.Time, after func(time.Duration) <-chan time.Time) {
+	runner.now = now
+	runner.after = after
+}
+
+// HasIndex reports whether the game has built its spatial index.
+func HasIndex(game *Game) bool {
+	return game.index != nil
+}
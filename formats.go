@@ -0,0 +1,211 @@
+package golife
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const sniff_length = 512
+
+// fileFormat ties a format name to the file suffixes it is known by, a
+// check that recognizes its contents, and the functions that read and write
+// it.  Any of the functions may be nil, e.g. images can only be written.
+type fileFormat struct {
+	name     string
+	suffixes []string
+	sniff    func([]byte) bool
+	reader   func(io.Reader) (*Game, error)
+	writer   func(*Game, io.Writer) error
+}
+
+var formatsMutex sync.RWMutex
+
+var fileFormats = []fileFormat{
+	{"rle", []string{".rle", ".rle.txt"}, sniffRLE, ReadRLE, (*Game).WriteRLE},
+	{"life", []string{".life", ".life.txt"}, sniffLife, ReadLife, (*Game).WriteLife},
+	{"cells", []string{".cells", ".cells.txt"}, sniffCells, ReadCells, (*Game).WriteCells},
+	{"json", []string{".json"}, sniffJSON, ReadJSON, (*Game).WriteJSON},
+	{"snapshot", []string{".lifesnap"}, sniffSnapshot, ReadSnapshot, (*Game).WriteSnapshot},
+	{"svg", []string{".svg"}, nil, nil, func(game *Game, w io.Writer) error {
+		return game.WriteSVG(w, DefaultSVGOptions())
+	}},
+	{"png", []string{".png"}, nil, nil, func(game *Game, w io.Writer) error {
+		return game.WritePNG(w, DefaultRenderOptions())
+	}},
+}
+
+// RegisterFormat adds a file format, or replaces the one already registered
+// under the same name.  Suffixes include the dot, e.g. ".rle"; when suffixes
+// of several formats match a file name, the longest one wins.  Sniff is
+// given the start of a file whose name doesn't match any suffix, and reports
+// whether it looks like this format.  Sniff, reader and writer may each be
+// nil if the format doesn't support them.  It returns a function that
+// puts back whatever was registered under the name before.
+func RegisterFormat(name string, suffixes []string, sniff func([]byte) bool, reader func(io.Reader) (*Game, error), writer func(*Game, io.Writer) error) func() {
+	format := fileFormat{name, append([]string(nil), suffixes...), sniff, reader, writer}
+
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	for i := range fileFormats {
+		if fileFormats[i].name == name {
+			previous := fileFormats[i]
+			fileFormats[i] = format
+			return func() {
+				restoreFormat(previous)
+			}
+		}
+	}
+	fileFormats = append(fileFormats, format)
+	return func() {
+		formatsMutex.Lock()
+		defer formatsMutex.Unlock()
+		fileFormats = slices.DeleteFunc(fileFormats, func(format fileFormat) bool {
+			return format.name == name
+		})
+	}
+}
+
+func restoreFormat(format fileFormat) {
+	formatsMutex.Lock()
+	defer formatsMutex.Unlock()
+	for i := range fileFormats {
+		if fileFormats[i].name == format.name {
+			fileFormats[i] = format
+			return
+		}
+	}
+	fileFormats = append(fileFormats, format)
+}
+
+func formatNamed(name string) (fileFormat, error) {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	for _, format := range fileFormats {
+		if format.name == name {
+			return format, nil
+		}
+	}
+	return fileFormat{}, fmt.Errorf("Unknown file format %q", name)
+}
+
+// FormatFor returns the name of the format a file is in judging by its
+// suffix, or an empty string if the suffix isn't known.
+func FormatFor(filepath string) string {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	name, longest := "", 0
+	for _, format := range fileFormats {
+		for _, suffix := range format.suffixes {
+			if len(suffix) > longest && strings.HasSuffix(filepath, suffix) {
+				name, longest = format.name, len(suffix)
+			}
+		}
+	}
+	return name
+}
+
+// SniffFormat returns the name of the first readable format that recognizes
+// the start of a file, or an empty string if none do.
+func SniffFormat(head []byte) string {
+	formatsMutex.RLock()
+	defer formatsMutex.RUnlock()
+	for _, format := range fileFormats {
+		if format.sniff != nil && format.reader != nil && format.sniff(head) {
+			return format.name
+		}
+	}
+	return ""
+}
+
+// Read reads a game in the named format, e.g. "rle", "cells" or "life".
+func Read(reader io.Reader, formatName string) (*Game, error) {
+	format, err := formatNamed(formatName)
+	if err != nil {
+		return nil, err
+	}
+	if format.reader == nil {
+		return nil, fmt.Errorf("Can't read %s files", formatName)
+	}
+	return format.reader(reader)
+}
+
+// Write writes the game in the named format, which besides the pattern
+// formats can be "svg" or "png" for a picture with the default options.
+func (game *Game) Write(writer io.Writer, formatName string) error {
+	format, err := formatNamed(formatName)
+	if err != nil {
+		return err
+	}
+	if format.writer == nil {
+		return fmt.Errorf("Can't write %s files", formatName)
+	}
+	return format.writer(game, writer)
+}
+
+// Convert reads a pattern in one format and writes it in another.
+func Convert(reader io.Reader, inFormat string, writer io.Writer, outFormat string) error {
+	if inFormat == "" || outFormat == "" {
+		return errors.New("Convert needs both an input and an output format")
+	}
+	if _, err := formatNamed(outFormat); err != nil {
+		return err
+	}
+	game, err := Read(reader, inFormat)
+	if err != nil {
+		return err
+	}
+	return game.Write(writer, outFormat)
+}
+
+// headerLines splits the start of a file into lines, leaving off the last
+// one since it may have been cut short.
+func headerLines(head []byte) []string {
+	lines := strings.Split(strings.ReplaceAll(string(head), "\r", ""), "\n")
+	if len(head) >= sniff_length && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func sniffRLE(head []byte) bool {
+	for _, line := range headerLines(head) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		return strings.HasPrefix(line, "x") && strings.Contains(line, "=")
+	}
+	return false
+}
+
+func sniffLife(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("#Life"))
+}
+
+func sniffCells(head []byte) bool {
+	found := false
+	for _, line := range headerLines(head) {
+		if strings.HasPrefix(line, "!") {
+			found = true
+			continue
+		}
+		if strings.Trim(line, ".O ") != "" {
+			return false
+		}
+		found = found || line != ""
+	}
+	return found
+}
+
+func sniffJSON(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte(`{"version":`))
+}
+
+func sniffSnapshot(head []byte) bool {
+	return bytes.HasPrefix(head, snapshot_magic)
+}
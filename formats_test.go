@@ -0,0 +1,181 @@
+package golife_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestFormatFor(t *testing.T) {
+	cases := map[string]string{
+		"glider.rle":       "rle",
+		"glider.rle.txt":   "rle",
+		"glider.cells":     "cells",
+		"dir/glider.life":  "life",
+		"glider.svg":       "svg",
+		"glider.something": "",
+	}
+	for path, expected := range cases {
+		if format := golife.FormatFor(path); format != expected {
+			t.Errorf("Expected format %q for %s, got %q", expected, path, format)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	var out strings.Builder
+	err := golife.Convert(strings.NewReader("x = 3, y = 1, rule = B3/S23\n3o!\n"), "rle", &out, "cells")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "OOO\n" {
+		t.Errorf("Expected a row of three cells, got %q", out.String())
+	}
+
+	if err := golife.Convert(strings.NewReader(""), "svg", &out, "rle"); err == nil {
+		t.Error("Converted from a format that can't be read")
+	}
+	if err := golife.Convert(strings.NewReader("OOO\n"), "cells", &out, "bogus"); err == nil {
+		t.Error("Converted to an unknown format")
+	}
+}
+
+func readCoords(reader io.Reader) (*golife.Game, error) {
+	game := golife.NewGame()
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var x, y golife.Coord
+		if _, err := fmt.Sscan(scanner.Text(), &x, &y); err != nil {
+			continue
+		}
+		game.AddCell(golife.Cell{X: x, Y: y})
+	}
+	return game, scanner.Err()
+}
+
+func writeCoords(game *golife.Game, writer io.Writer) error {
+	fmt.Fprintln(writer, "coords")
+	for cell := range game.Population.All() {
+		if _, err := fmt.Fprintln(writer, cell.X, cell.Y); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRegisterFormat(t *testing.T) {
+	sniff := func(head []byte) bool { return strings.HasPrefix(string(head), "coords\n") }
+	t.Cleanup(golife.RegisterFormat("coords", []string{".xy"}, sniff, readCoords, writeCoords))
+
+	dir := t.TempDir()
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}})
+	if err := game.Save(filepath.Join(dir, "blinker.xy")); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := golife.Load(filepath.Join(dir, "blinker.xy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matching, errmsg := cmpPops(game.Population, loaded.Population); !matching {
+		t.Error("Registered format didn't round trip: ", errmsg)
+	}
+
+	if err := os.Rename(filepath.Join(dir, "blinker.xy"), filepath.Join(dir, "blinker.dat")); err != nil {
+		t.Fatal(err)
+	}
+	sniffed, err := golife.Load(filepath.Join(dir, "blinker.dat"))
+	if err != nil {
+		t.Fatal("Couldn't load file by sniffing its contents: ", err)
+	}
+	if sniffed.Size() != 3 {
+		t.Errorf("Expected 3 cells from sniffed file, got %d", sniffed.Size())
+	}
+
+	if err := game.Save(filepath.Join(dir, "blinker.unknown")); err == nil {
+		t.Error("Saved a file with an unknown suffix")
+	}
+}
+
+func TestRegisterFormatUndo(t *testing.T) {
+	unregister := golife.RegisterFormat("coords", []string{".xy"}, nil, readCoords, writeCoords)
+	restore := golife.RegisterFormat("cells", []string{".xy.cells"}, nil, readCoords, writeCoords)
+	if golife.FormatFor("glider.xy") != "coords" || golife.FormatFor("glider.xy.cells") != "cells" || golife.FormatFor("glider.cells") != "" {
+		t.Error("Formats not registered")
+	}
+
+	restore()
+	unregister()
+	if format := golife.FormatFor("glider.xy"); format != "" {
+		t.Errorf("Expected no format for glider.xy once unregistered, got %q", format)
+	}
+	if format := golife.FormatFor("glider.cells"); format != "cells" {
+		t.Errorf("Expected the built in cells format back, got %q", format)
+	}
+	var out strings.Builder
+	if err := golife.Convert(strings.NewReader("x = 3, y = 1, rule = B3/S23\n3o!\n"), "rle", &out, "cells"); err != nil || out.String() != "OOO\n" {
+		t.Errorf("Built in cells format not restored, wrote %q (%v)", out.String(), err)
+	}
+}
+
+// TestFormatsRoundTrip writes a pattern in each registered format that can
+// be read back, through the registry by name, and checks the contents are
+// recognized and read back the same.  The image formats are only written.
+func TestFormatsRoundTrip(t *testing.T) {
+	game := golife.NewGame()
+	game.Name = "Glider"
+	game.AddCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+
+	for _, name := range []string{"rle", "cells", "life", "json", "snapshot"} {
+		var out strings.Builder
+		if err := game.Write(&out, name); err != nil {
+			t.Errorf("Writing %s: %v", name, err)
+			continue
+		}
+		if format := golife.SniffFormat([]byte(out.String())); format != name {
+			t.Errorf("%s output sniffed as %q", name, format)
+		}
+		readBack, err := golife.Read(strings.NewReader(out.String()), name)
+		if err != nil {
+			t.Errorf("Reading %s back: %v", name, err)
+			continue
+		}
+		if matching, errmsg := cmpPops(game.Population, readBack.Population); !matching {
+			t.Errorf("%s didn't round trip: %s", name, errmsg)
+		}
+		if name != "cells" && readBack.Name != game.Name {
+			t.Errorf("%s lost the name, got %q", name, readBack.Name)
+		}
+	}
+
+	for _, name := range []string{"svg", "png"} {
+		var out strings.Builder
+		if err := game.Write(&out, name); err != nil || out.Len() == 0 {
+			t.Errorf("Writing %s gave %d bytes: %v", name, out.Len(), err)
+		}
+		if _, err := golife.Read(strings.NewReader(out.String()), name); err == nil {
+			t.Errorf("Read a %s file", name)
+		}
+	}
+}
+
+func TestSniffFormat(t *testing.T) {
+	cases := map[string]string{
+		"#N Glider\nx = 3, y = 3, rule = B3/S23\nbo$2bo$3o!\n": "rle",
+		"!Name: Glider\n.O\n..O\nOOO\n":                        "cells",
+		"#Life 1.05\n*\n":                                      "life",
+		"something else entirely\n":                            "",
+	}
+	for head, expected := range cases {
+		if format := golife.SniffFormat([]byte(head)); format != expected {
+			t.Errorf("Expected %q for %q, got %q", expected, head, format)
+		}
+	}
+}
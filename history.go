@@ -0,0 +1,249 @@
+package golife
+
+import (
+	"errors"
+	"io"
+)
+
+type generationDelta struct {
+	births, deaths []Cell
+}
+
+// deltaHistory keeps the births and deaths between successive generations,
+// plus a full copy of the population every interval generations, so going
+// back a step only touches the cells that changed.
+type deltaHistory struct {
+	interval  int
+	base      int
+	deltas    []generationDelta
+	keyframes map[int]Population
+	dirty     bool
+}
+
+func newDeltaHistory(interval int, pop Population, generation int) *deltaHistory {
+	var history deltaHistory
+	history.interval = interval
+	history.base = generation
+	history.deltas = make([]generationDelta, 0, 10)
+	history.keyframes = make(map[int]Population)
+	history.keyframes[generation] = pop.copy()
+	return &history
+}
+
+func (pop Population) copy() Population {
+	newpop := make(Population, len(pop))
+	for cell, present := range pop {
+		if present {
+			newpop[cell] = true
+		}
+	}
+	return newpop
+}
+
+func diffPopulations(current, next Population) generationDelta {
+	var delta generationDelta
+	for cell, present := range next {
+		if present && !current[cell] {
+			delta.births = append(delta.births, cell)
+		}
+	}
+	for cell, present := range current {
+		if present && !next[cell] {
+			delta.deaths = append(delta.deaths, cell)
+		}
+	}
+	return delta
+}
+
+// SetKeyframeInterval switches the history kept by Next from a full
+// Population per generation to the births and deaths between generations,
+// with a full copy every interval generations for SeekTo to rebuild from.
+// An interval of 0 goes back to full snapshots.  Either way the existing
+// history is discarded.
+func (game *Game) SetKeyframeInterval(interval int) {
+	if interval > 0 {
+		game.deltas = newDeltaHistory(interval, game.Population, game.Generation)
+	} else {
+		game.deltas = nil
+	}
+	if game.History != nil {
+		game.History = game.History[:0]
+	}
+}
+
+func (game *Game) recordDelta(delta generationDelta, next Population) {
+	history := game.deltas
+	if game.HistorySize == 0 {
+		history.base = game.Generation + 1
+		history.deltas = history.deltas[:0]
+		clear(history.keyframes)
+		return
+	}
+
+	history.deltas = append(history.deltas, delta)
+	if (game.Generation+1)%history.interval == 0 {
+		history.keyframes[game.Generation+1] = next.copy()
+	}
+	if game.HistorySize > 0 {
+		history.trim(game.HistorySize)
+	}
+}
+
+// trim drops the oldest deltas beyond size, rolling the keyframe at the
+// base forward over them so there is always one to rebuild from.
+func (history *deltaHistory) trim(size int) {
+	for len(history.deltas) > size {
+		delta := history.deltas[0]
+		history.deltas = history.deltas[1:]
+		if pop, found := history.keyframes[history.base]; found {
+			delete(history.keyframes, history.base)
+			if _, found := history.keyframes[history.base+1]; !found {
+				for _, cell := range delta.deaths {
+					delete(pop, cell)
+				}
+				pop.Add(delta.births)
+				history.keyframes[history.base+1] = pop
+			}
+		}
+		history.base += 1
+	}
+	for generation := range history.keyframes {
+		if generation < history.base {
+			delete(history.keyframes, generation)
+		}
+	}
+}
+
+// rebuild returns a copy of the population at a generation in the history,
+// worked out from the nearest keyframe before it.
+func (history *deltaHistory) rebuild(generation int) (Population, bool) {
+	keyframe := -1
+	for kf := range history.keyframes {
+		if kf <= generation && kf > keyframe {
+			keyframe = kf
+		}
+	}
+	if keyframe < history.base || generation > history.base+len(history.deltas) {
+		return nil, false
+	}
+	pop := history.keyframes[keyframe].copy()
+	for _, delta := range history.deltas[keyframe-history.base : generation-history.base] {
+		for _, cell := range delta.deaths {
+			delete(pop, cell)
+		}
+		pop.Add(delta.births)
+	}
+	return pop, true
+}
+
+// saveEditedDelta folds edits made since the last step into the delta that
+// led to the current generation, so that going back from the edited
+// population still arrives at the previous generation as it was.
+func (game *Game) saveEditedDelta() {
+	history := game.deltas
+	if history == nil || !history.dirty {
+		return
+	}
+	history.dirty = false
+	if game.HistorySize == 0 {
+		return
+	}
+
+	if len(history.deltas) == 0 {
+		history.keyframes[game.Generation] = game.Population.copy()
+		return
+	}
+	previous, found := history.rebuild(game.Generation - 1)
+	if !found {
+		game.deltas = newDeltaHistory(history.interval, game.Population, game.Generation)
+		return
+	}
+	history.deltas[len(history.deltas)-1] = diffPopulations(previous, game.Population)
+	if _, found := history.keyframes[game.Generation]; found {
+		history.keyframes[game.Generation] = game.Population.copy()
+	}
+}
+
+func (game *Game) previousDelta() (generationDelta, error) {
+	game.saveEditedDelta()
+	history := game.deltas
+	if game.HistorySize == 0 || len(history.deltas) == 0 {
+		return generationDelta{}, io.EOF
+	}
+
+	delta := history.deltas[len(history.deltas)-1]
+	history.deltas = history.deltas[:len(history.deltas)-1]
+	delete(history.keyframes, game.Generation)
+	for _, cell := range delta.births {
+		delete(game.Population, cell)
+	}
+	game.Population.Add(delta.deaths)
+	game.Generation -= 1
+	game.rewindTimeline()
+	game.updateIndexDelta(delta.deaths, delta.births)
+	return delta, nil
+}
+
+// SeekTo moves the game to the given generation.  Later generations are
+// simply run forward with Next.  Earlier ones have to still be in the
+// history, and are rebuilt from the nearest keyframe when one is closer than
+// the current generation.  As with Previous, the history after the target
+// generation is discarded.  With the journal on, the move is undone as one
+// entry, and going back is always done a generation at a time so that the
+// history can be restored by Undo.
+func (game *Game) SeekTo(generation int) error {
+	game.BeginEdit("seek")
+	defer game.EndEdit()
+	for game.Generation < generation {
+		game.Next()
+	}
+	if game.Generation == generation {
+		return nil
+	}
+
+	if game.deltas == nil {
+		if game.Generation-generation > len(game.History) {
+			return errors.New("Generation is no longer in history")
+		}
+		for game.Generation > generation {
+			game.Previous()
+		}
+		return nil
+	}
+
+	game.saveEditedDelta()
+	history := game.deltas
+	if generation < history.base {
+		return errors.New("Generation is no longer in history")
+	}
+
+	keyframe := -1
+	for kf := range history.keyframes {
+		if kf <= generation && kf > keyframe {
+			keyframe = kf
+		}
+	}
+
+	if keyframe >= 0 && generation-keyframe < game.Generation-generation && game.journal == nil {
+		pop, _ := history.rebuild(generation)
+		history.deltas = history.deltas[:generation-history.base]
+		for kf := range history.keyframes {
+			if kf > generation {
+				delete(history.keyframes, kf)
+			}
+		}
+		from := game.Generation
+		game.Population = pop
+		game.Generation = generation
+		game.rewindTimeline()
+		game.invalidateIndex()
+		game.resetStatus()
+		game.fire(JumpEvent, from, nil, nil)
+		return nil
+	}
+
+	for game.Generation > generation {
+		game.Previous()
+	}
+	return nil
+}
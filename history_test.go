@@ -0,0 +1,159 @@
+package golife_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestDeltaHistoryPrevious(t *testing.T) {
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	game.SetHistorySize(-1)
+	game.SetKeyframeInterval(8)
+
+	saved := make([]golife.Population, 0, 20)
+	for range 20 {
+		saved = append(saved, game.Copy().Population)
+		game.Next()
+	}
+
+	for gen := 19; gen >= 0; gen-- {
+		if err := game.Previous(); err != nil {
+			t.Fatal(err)
+		}
+		if game.Generation != gen {
+			t.Errorf("Expected generation %d, got %d", gen, game.Generation)
+		}
+		if match, errmsg := samePop(saved[gen], game.Population); !match {
+			t.Errorf("Generation %d not restored: %s", gen, errmsg)
+		}
+	}
+
+	if err := game.Previous(); err == nil {
+		t.Error("Went back past the start of history")
+	}
+}
+
+func TestSeekTo(t *testing.T) {
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	reference := game.Copy()
+	game.SetHistorySize(-1)
+	game.SetKeyframeInterval(5)
+
+	if err := game.SeekTo(30); err != nil || game.Generation != 30 {
+		t.Fatalf("Seek forward failed: %v at generation %d", err, game.Generation)
+	}
+
+	for range 11 {
+		reference.Next()
+	}
+	if err := game.SeekTo(11); err != nil {
+		t.Fatal(err)
+	}
+	if match, errmsg := samePop(reference.Population, game.Population); game.Generation != 11 || !match {
+		t.Errorf("Seek back to generation 11 failed: %s", errmsg)
+	}
+
+	game.Next()
+	reference.Next()
+	if match, errmsg := samePop(reference.Population, game.Population); !match {
+		t.Errorf("History diverged after seeking: %s", errmsg)
+	}
+}
+
+func TestDeltaHistorySize(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	game.SetHistorySize(3)
+	game.SetKeyframeInterval(2)
+
+	for range 10 {
+		game.Next()
+	}
+	if err := game.SeekTo(7); err != nil {
+		t.Errorf("Generation 7 should still be in history: %v", err)
+	}
+	if err := game.SeekTo(6); err == nil {
+		t.Error("Generation 6 should have been dropped from history")
+	}
+}
+
+func samePop(pop1, pop2 golife.Population) (bool, string) {
+	if pop1.Size() != pop2.Size() {
+		return false, fmt.Sprintf("sizes %d and %d differ", pop1.Size(), pop2.Size())
+	}
+	for cell := range pop1 {
+		if !pop2[cell] {
+			return false, fmt.Sprintf("cell %v missing", cell)
+		}
+	}
+	return true, ""
+}
+
+// editedGame runs a blinker, then adds a row of cells to it without
+// stepping again.
+func editedGame(keyframeInterval, historySize int) *golife.Game {
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}})
+	game.SetHistorySize(historySize)
+	game.SetKeyframeInterval(keyframeInterval)
+	for range 3 {
+		game.Next()
+	}
+	game.AddCells(golife.CellList{{10, 10}, {11, 10}, {12, 10}})
+	return game
+}
+
+func TestHistoryModesAgreeAfterEdit(t *testing.T) {
+	for _, historySize := range []int{-1, 2} {
+		full := editedGame(0, historySize)
+		for _, interval := range []int{1, 2, 8} {
+			game := editedGame(interval, historySize)
+			reference := editedGame(0, historySize)
+			for {
+				err, referenceErr := game.Previous(), reference.Previous()
+				if (err == nil) != (referenceErr == nil) {
+					t.Fatalf("Interval %d: Previous gave %v, full history gave %v", interval, err, referenceErr)
+				}
+				if err != nil {
+					break
+				}
+				if match, errmsg := samePop(reference.Population, game.Population); !match || game.Generation != reference.Generation {
+					t.Fatalf("Interval %d differs at generation %d: %s", interval, game.Generation, errmsg)
+				}
+			}
+
+			game = editedGame(interval, historySize)
+			if err := game.SeekTo(2); err != nil {
+				t.Fatal(err)
+			}
+			if err := full.SeekTo(2); err != nil {
+				t.Fatal(err)
+			}
+			if match, errmsg := samePop(full.Population, game.Population); !match {
+				t.Errorf("Interval %d: SeekTo(2) after an edit differs: %s", interval, errmsg)
+			}
+			full = editedGame(0, historySize)
+		}
+	}
+}
+
+func TestEditThenStepKeepsHistory(t *testing.T) {
+	game := editedGame(2, -1)
+	reference := editedGame(0, -1)
+	for range 3 {
+		game.Next()
+		reference.Next()
+	}
+	for reference.Previous() == nil {
+		if err := game.Previous(); err != nil {
+			t.Fatal(err)
+		}
+		if match, errmsg := samePop(reference.Population, game.Population); !match {
+			t.Fatalf("Generation %d differs: %s", game.Generation, errmsg)
+		}
+	}
+}
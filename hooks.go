@@ -0,0 +1,108 @@
+package golife
+
+import (
+	"sync"
+)
+
+type EventKind int
+
+const (
+	NextEvent EventKind = iota
+	PreviousEvent
+	EditEvent
+	JumpEvent
+)
+
+func (kind EventKind) String() string {
+	switch kind {
+	case NextEvent:
+		return "next"
+	case PreviousEvent:
+		return "previous"
+	case EditEvent:
+		return "edit"
+	default:
+		return "jump"
+	}
+}
+
+// GameEvent describes a change to a game's population.  Births and Deaths
+// are the cells that changed going from generation From to To.  A
+// JumpEvent, where the population was replaced outright by SeekTo or GoTo,
+// has neither.
+type GameEvent struct {
+	Kind           EventKind
+	From, To       int
+	Births, Deaths []Cell
+}
+
+// Hook is called after the change has been made, so game holds the new
+// population.  Hooks must not modify the game.
+type Hook func(game *Game, event GameEvent)
+
+type hookEntry struct {
+	id   int
+	hook Hook
+}
+
+var hooksMutex sync.Mutex
+var loadHooks []hookEntry
+var nextHookId int
+
+// AddHook registers a hook to be called on every step, edit or jump of the
+// game, and returns a function that removes it again.
+func (game *Game) AddHook(hook Hook) func() {
+	hooksMutex.Lock()
+	nextHookId += 1
+	id := nextHookId
+	hooksMutex.Unlock()
+	game.hooks = append(game.hooks, hookEntry{id, hook})
+	return func() {
+		game.hooks = removeHook(game.hooks, id)
+	}
+}
+
+// AddLoadHook registers a hook to be called with every game read by Load,
+// which is also the place to attach hooks to the game itself.
+func AddLoadHook(hook func(game *Game)) func() {
+	hooksMutex.Lock()
+	defer hooksMutex.Unlock()
+	nextHookId += 1
+	id := nextHookId
+	loadHooks = append(loadHooks, hookEntry{id, func(game *Game, event GameEvent) {
+		hook(game)
+	}})
+	return func() {
+		hooksMutex.Lock()
+		defer hooksMutex.Unlock()
+		loadHooks = removeHook(loadHooks, id)
+	}
+}
+
+func removeHook(hooks []hookEntry, id int) []hookEntry {
+	for i := range hooks {
+		if hooks[i].id == id {
+			return append(hooks[:i:i], hooks[i+1:]...)
+		}
+	}
+	return hooks
+}
+
+func (game *Game) fire(kind EventKind, from int, births, deaths []Cell) {
+	if len(game.hooks) == 0 {
+		return
+	}
+	event := GameEvent{kind, from, game.Generation, births, deaths}
+	for _, entry := range game.hooks {
+		entry.hook(game, event)
+	}
+}
+
+func fireLoadHooks(game *Game) {
+	hooksMutex.Lock()
+	hooks := loadHooks
+	hooksMutex.Unlock()
+	for _, entry := range hooks {
+		entry.hook(game, GameEvent{})
+	}
+}
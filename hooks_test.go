@@ -0,0 +1,63 @@
+package golife_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestHooks(t *testing.T) {
+	game := golife.NewGame()
+	game.SetHistorySize(-1)
+	events := make([]golife.GameEvent, 0)
+	remove := game.AddHook(func(g *golife.Game, event golife.GameEvent) {
+		events = append(events, event)
+	})
+
+	game.AddCells(testPattern)
+	game.Next()
+	game.Previous()
+	game.RemoveCell(golife.Cell{0, 0})
+	remove()
+	game.AddCell(golife.Cell{0, 0})
+
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %d: %+v", len(events), events)
+	}
+	if events[0].Kind != golife.EditEvent || len(events[0].Births) != 3 {
+		t.Errorf("Unexpected edit event %+v", events[0])
+	}
+	next := events[1]
+	if next.Kind != golife.NextEvent || next.From != 0 || next.To != 1 || len(next.Births) != 2 || len(next.Deaths) != 2 {
+		t.Errorf("Unexpected next event %+v", next)
+	}
+	previous := events[2]
+	if previous.Kind != golife.PreviousEvent || previous.From != 1 || previous.To != 0 || len(previous.Births) != 2 || len(previous.Deaths) != 2 {
+		t.Errorf("Unexpected previous event %+v", previous)
+	}
+	if events[3].Kind != golife.EditEvent || len(events[3].Deaths) != 1 || events[3].Deaths[0] != (golife.Cell{0, 0}) {
+		t.Errorf("Unexpected remove event %+v", events[3])
+	}
+}
+
+func TestLoadHook(t *testing.T) {
+	var loaded *golife.Game
+	remove := golife.AddLoadHook(func(game *golife.Game) {
+		loaded = game
+	})
+	defer remove()
+
+	path := filepath.Join(t.TempDir(), "blinker.cells")
+	if err := os.WriteFile(path, []byte("OOO\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	game, err := golife.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded != game {
+		t.Error("Load hook not called with the loaded game")
+	}
+}
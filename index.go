@@ -0,0 +1,282 @@
+package golife
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"reflect"
+)
+
+const (
+	tile_shift = 6
+	tile_size  = 1 << tile_shift
+	tile_mask  = tile_size - 1
+)
+
+// A tile is a 64x64 block of cells, one bit per cell, with a row per word.
+type tile [tile_size]uint64
+
+// tileIndex is a spatial index of a game's population, kept up to date by
+// the Game's own methods once EnableIndex has turned it on.  It remembers
+// which map it was built from and how many cells it holds, and is rebuilt
+// if either no longer matches, but an edit made directly to Population
+// that leaves the size alone can only be caught by PopulationChanged.
+type tileIndex struct {
+	tiles map[Cell]*tile
+	pop   uintptr
+	size  int
+}
+
+func tileKey(cell Cell) (Cell, int, int) {
+	return Cell{cell.X >> tile_shift, cell.Y >> tile_shift}, int(cell.X & tile_mask), int(cell.Y & tile_mask)
+}
+
+// EnableIndex turns the spatial index behind CellsInRect, CountInRect and
+// BoundingBox on or off.  It's built the first time one of them needs it.
+// Without it they look at every cell.
+func (game *Game) EnableIndex(enabled bool) {
+	game.indexed = enabled
+	game.invalidateIndex()
+}
+
+// spatialIndex returns the index, building it if it's missing or stale, or
+// nil if the index is off.
+func (game *Game) spatialIndex() *tileIndex {
+	if !game.indexed {
+		return nil
+	}
+	pop := reflect.ValueOf(game.Population).Pointer()
+	if index := game.index; index != nil && index.pop == pop && index.size == len(game.Population) {
+		return index
+	}
+
+	index := &tileIndex{tiles: make(map[Cell]*tile), pop: pop}
+	for cell, present := range game.Population {
+		if present {
+			index.set(cell, true)
+		}
+	}
+	game.index = index
+	return index
+}
+
+func (game *Game) invalidateIndex() {
+	game.index = nil
+}
+
+// updateIndex keeps an index, if there is one, in step with an edit that
+// has just been made to the population.
+func (game *Game) updateIndex(cell Cell, alive bool) {
+	if game.index != nil {
+		game.index.set(cell, alive)
+	}
+}
+
+// updateIndexDelta does the same for a whole step's worth of births and
+// deaths.
+func (game *Game) updateIndexDelta(births, deaths []Cell) {
+	if game.index == nil {
+		return
+	}
+	for _, cell := range deaths {
+		game.index.set(cell, false)
+	}
+	for _, cell := range births {
+		game.index.set(cell, true)
+	}
+}
+
+func (index *tileIndex) set(cell Cell, alive bool) {
+	key, x, y := tileKey(cell)
+	t := index.tiles[key]
+	if alive {
+		if t == nil {
+			t = new(tile)
+			index.tiles[key] = t
+		}
+		if t[y]&(1<<x) == 0 {
+			index.size += 1
+		}
+		t[y] |= 1 << x
+	} else if t != nil {
+		if t[y]&(1<<x) != 0 {
+			index.size -= 1
+		}
+		t[y] &^= 1 << x
+		if *t == (tile{}) {
+			delete(index.tiles, key)
+		}
+	}
+}
+
+// visit calls f for each tile overlapping the rectangle, with the rows of
+// the tile that are in it and a mask of the columns that are.
+func (index *tileIndex) visit(min_cell, max_cell Cell, f func(key Cell, t *tile, first, last int, mask uint64)) {
+	if min_cell.X > max_cell.X || min_cell.Y > max_cell.Y {
+		return
+	}
+	min_key, _, _ := tileKey(min_cell)
+	max_key, _, _ := tileKey(max_cell)
+
+	each := func(key Cell, t *tile) {
+		if key.X < min_key.X || key.X > max_key.X || key.Y < min_key.Y || key.Y > max_key.Y {
+			return
+		}
+		first, last := 0, tile_mask
+		if key.Y == min_key.Y {
+			first = int(min_cell.Y & tile_mask)
+		}
+		if key.Y == max_key.Y {
+			last = int(max_cell.Y & tile_mask)
+		}
+		mask := ^uint64(0)
+		if key.X == min_key.X {
+			mask &= ^uint64(0) << (min_cell.X & tile_mask)
+		}
+		if key.X == max_key.X {
+			mask &= ^uint64(0) >> (tile_mask - max_cell.X&tile_mask)
+		}
+		f(key, t, first, last, mask)
+	}
+
+	width := uint64(max_key.X - min_key.X)
+	height := uint64(max_key.Y - min_key.Y)
+	if width < uint64(len(index.tiles)) && height < uint64(len(index.tiles)) && (width+1)*(height+1) <= uint64(len(index.tiles)) {
+		for ty := min_key.Y; ty <= max_key.Y; ty++ {
+			for tx := min_key.X; tx <= max_key.X; tx++ {
+				key := Cell{tx, ty}
+				if t, found := index.tiles[key]; found {
+					each(key, t)
+				}
+			}
+		}
+	} else {
+		for key, t := range index.tiles {
+			each(key, t)
+		}
+	}
+}
+
+func inRect(cell, min_cell, max_cell Cell) bool {
+	return cell.X >= min_cell.X && cell.X <= max_cell.X && cell.Y >= min_cell.Y && cell.Y <= max_cell.Y
+}
+
+// CellsInRect returns the live cells within the rectangle from min_cell to
+// max_cell inclusive, looking only at the parts of the population that
+// overlap it when the index is on.
+func (game *Game) CellsInRect(min_cell, max_cell Cell) []Cell {
+	cells := make([]Cell, 0)
+	index := game.spatialIndex()
+	if index == nil {
+		for cell, present := range game.Population {
+			if present && inRect(cell, min_cell, max_cell) {
+				cells = append(cells, cell)
+			}
+		}
+		return cells
+	}
+	index.visit(min_cell, max_cell, func(key Cell, t *tile, first, last int, mask uint64) {
+		for y := first; y <= last; y++ {
+			row := t[y] & mask
+			for row != 0 {
+				x := bits.TrailingZeros64(row)
+				row &= row - 1
+				cells = append(cells, Cell{key.X<<tile_shift + Coord(x), key.Y<<tile_shift + Coord(y)})
+			}
+		}
+	})
+	return cells
+}
+
+func (game *Game) CountInRect(min_cell, max_cell Cell) int {
+	count := 0
+	index := game.spatialIndex()
+	if index == nil {
+		for cell, present := range game.Population {
+			if present && inRect(cell, min_cell, max_cell) {
+				count += 1
+			}
+		}
+		return count
+	}
+	index.visit(min_cell, max_cell, func(key Cell, t *tile, first, last int, mask uint64) {
+		for y := first; y <= last; y++ {
+			count += bits.OnesCount64(t[y] & mask)
+		}
+	})
+	return count
+}
+
+// BoundingBox is the same as Population.BoundingBox, but works from the
+// spatial index, when it's on, rather than looking at every cell.
+func (game *Game) BoundingBox() (Cell, Cell) {
+	index := game.spatialIndex()
+	if index == nil {
+		return game.Population.BoundingBox()
+	}
+
+	var min_cell, max_cell Cell
+	min_cell.X = math.MaxInt64
+	min_cell.Y = math.MaxInt64
+	max_cell.X = math.MinInt64
+	max_cell.Y = math.MinInt64
+
+	for key, t := range index.tiles {
+		base_x, base_y := key.X<<tile_shift, key.Y<<tile_shift
+		if base_x > min_cell.X && base_x+tile_mask < max_cell.X && base_y > min_cell.Y && base_y+tile_mask < max_cell.Y {
+			continue
+		}
+		var columns uint64
+		first, last := -1, 0
+		for y := range t {
+			if t[y] != 0 {
+				columns |= t[y]
+				if first < 0 {
+					first = y
+				}
+				last = y
+			}
+		}
+		min_cell.X = min(min_cell.X, base_x+Coord(bits.TrailingZeros64(columns)))
+		max_cell.X = max(max_cell.X, base_x+Coord(tile_mask-bits.LeadingZeros64(columns)))
+		min_cell.Y = min(min_cell.Y, base_y+Coord(first))
+		max_cell.Y = max(max_cell.Y, base_y+Coord(last))
+	}
+
+	return min_cell, max_cell
+}
+
+// ClearRect removes every live cell in the rectangle, as a single edit.
+func (game *Game) ClearRect(min_cell, max_cell Cell) {
+	game.BeginEdit("clear")
+	game.changeCells(nil, game.CellsInRect(min_cell, max_cell), true)
+	game.EndEdit()
+}
+
+// FillRect makes every cell in the rectangle live.
+func (game *Game) FillRect(min_cell, max_cell Cell) {
+	game.RandomFillRect(min_cell, max_cell, 1, nil)
+}
+
+// RandomFillRect makes each cell in the rectangle live with the given
+// probability, leaving the rest as they were, as a single edit.  Cells are visited row by
+// row, drawing one Float64 from rng for each, so the same generator state
+// always gives the same result.  A nil rng uses the math/rand default
+// source.
+func (game *Game) RandomFillRect(min_cell, max_cell Cell, density float64, rng *rand.Rand) {
+	random := rand.Float64
+	if rng != nil {
+		random = rng.Float64
+	}
+	births := make([]Cell, 0)
+	for y := min_cell.Y; y <= max_cell.Y; y++ {
+		for x := min_cell.X; x <= max_cell.X; x++ {
+			if density >= 1 || random() < density {
+				births = append(births, Cell{x, y})
+			}
+		}
+	}
+	game.BeginEdit("fill")
+	game.changeCells(births, nil, true)
+	game.EndEdit()
+}
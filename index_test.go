@@ -0,0 +1,206 @@
+package golife_test
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func soupGame(seed string, generations int) *golife.Game {
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed(seed)
+	game.EnableIndex(true)
+	for range generations {
+		game.Next()
+	}
+	return game
+}
+
+func TestCellsInRect(t *testing.T) {
+	game := soupGame("k_test0", 150)
+	rng := rand.New(rand.NewSource(1))
+	min_cell, _ := game.Population.BoundingBox()
+
+	for range 50 {
+		corner := golife.Cell{min_cell.X - 5 + golife.Coord(rng.Intn(100)), min_cell.Y - 5 + golife.Coord(rng.Intn(100))}
+		far := golife.Cell{corner.X + golife.Coord(rng.Intn(100)), corner.Y + golife.Coord(rng.Intn(100))}
+		expected := slices.Collect(game.Population.InRect(corner, far))
+		got := golife.CellList(game.CellsInRect(corner, far))
+		sort.Sort(got)
+		if !slices.Equal(expected, []golife.Cell(got)) {
+			t.Fatalf("Cells in %v-%v differ: %v and %v", corner, far, expected, got)
+		}
+		if count := game.CountInRect(corner, far); count != len(expected) {
+			t.Fatalf("Expected %d cells in %v-%v, counted %d", len(expected), corner, far, count)
+		}
+	}
+}
+
+func TestIndexedBoundingBox(t *testing.T) {
+	game := soupGame("k_test1", 0)
+	for range 100 {
+		min_cell, max_cell := game.Population.BoundingBox()
+		index_min, index_max := game.BoundingBox()
+		if min_cell != index_min || max_cell != index_max {
+			t.Fatalf("Bounding boxes differ at generation %d: %v-%v and %v-%v", game.Generation, min_cell, max_cell, index_min, index_max)
+		}
+		game.Next()
+		game.AddCell(golife.Cell{golife.Coord(-game.Generation), 0})
+	}
+}
+
+func TestFillAndClearRect(t *testing.T) {
+	game := golife.NewGame()
+	game.FillRect(golife.Cell{-10, -10}, golife.Cell{9, 9})
+	if game.Size() != 400 || game.CountInRect(golife.Cell{-10, -10}, golife.Cell{-1, -1}) != 100 {
+		t.Errorf("Unexpected fill, %d cells", game.Size())
+	}
+
+	game.ClearRect(golife.Cell{-5, -5}, golife.Cell{4, 4})
+	if game.Size() != 300 || game.HasCell(golife.Cell{0, 0}) || !game.HasCell(golife.Cell{5, 5}) {
+		t.Errorf("Unexpected clear, %d cells", game.Size())
+	}
+
+	first, second := golife.NewGame(), golife.NewGame()
+	first.RandomFillRect(golife.Cell{0, 0}, golife.Cell{31, 31}, 0.3, rand.New(rand.NewSource(42)))
+	second.RandomFillRect(golife.Cell{0, 0}, golife.Cell{31, 31}, 0.3, rand.New(rand.NewSource(42)))
+	if match, errmsg := samePop(first.Population, second.Population); !match {
+		t.Errorf("Same seed gave different fills: %s", errmsg)
+	}
+	if first.Size() < 200 || first.Size() > 400 {
+		t.Errorf("Fill at 30%% gave %d of 1024 cells", first.Size())
+	}
+}
+
+func TestIndexAfterDirectEdit(t *testing.T) {
+	game := golife.NewGame()
+	game.EnableIndex(true)
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}})
+	if count := game.CountInRect(golife.Cell{-10, -10}, golife.Cell{200, 200}); count != 3 {
+		t.Fatalf("Expected 3 cells, counted %d", count)
+	}
+
+	delete(game.Population, golife.Cell{0, 0})
+	game.Population[golife.Cell{100, 100}] = true
+	game.PopulationChanged()
+	if count := game.CountInRect(golife.Cell{-10, -10}, golife.Cell{200, 200}); count != 3 {
+		t.Errorf("Expected 3 cells after the edit, counted %d", count)
+	}
+	min_cell, max_cell := game.BoundingBox()
+	if min_cell != (golife.Cell{1, 0}) || max_cell != (golife.Cell{100, 100}) {
+		t.Errorf("Expected bounding box {1 0}-{100 100}, got %v-%v", min_cell, max_cell)
+	}
+}
+
+func TestIndexFollowsStepsAndHistory(t *testing.T) {
+	for _, interval := range []int{0, 4} {
+		game := golife.NewGame()
+		game.Population = golife.SoupFromSeed("k_test0")
+		game.EnableIndex(true)
+		game.SetHistorySize(20)
+		game.SetKeyframeInterval(interval)
+		game.EnableJournal(-1)
+		check := func(what string) {
+			min_cell, max_cell := game.Population.BoundingBox()
+			index_min, index_max := game.BoundingBox()
+			if min_cell != index_min || max_cell != index_max {
+				t.Fatalf("Bounding boxes differ after %s at generation %d", what, game.Generation)
+			}
+			if count := game.CountInRect(min_cell, max_cell); count != game.Size() {
+				t.Fatalf("Counted %d of %d cells after %s", count, game.Size(), what)
+			}
+		}
+		for range 15 {
+			check("step")
+			game.Next()
+		}
+		game.Undo()
+		check("undo")
+		game.Previous()
+		check("previous")
+		game.RandomFillRect(golife.Cell{-8, -8}, golife.Cell{-1, -1}, 0.5, nil)
+		check("fill")
+		game.Next()
+		check("step after fill")
+	}
+}
+
+func TestIndexIsExplicit(t *testing.T) {
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	min_cell, max_cell := game.BoundingBox()
+	game.CellsInRect(min_cell, max_cell)
+	if golife.HasIndex(game) {
+		t.Error("Queries built an index that wasn't turned on")
+	}
+	if count := game.CountInRect(min_cell, max_cell); count != game.Size() {
+		t.Errorf("Counted %d of %d cells without the index", count, game.Size())
+	}
+
+	game.EnableIndex(true)
+	game.BoundingBox()
+	if !golife.HasIndex(game) {
+		t.Error("No index after turning it on")
+	}
+	game.EnableIndex(false)
+	if golife.HasIndex(game) {
+		t.Error("Index kept after turning it off")
+	}
+}
+
+func TestStaleIndex(t *testing.T) {
+	game := golife.NewGame()
+	game.EnableIndex(true)
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}})
+	everything_min, everything_max := golife.Cell{-100, -100}, golife.Cell{100, 100}
+	game.CountInRect(everything_min, everything_max)
+
+	// Replaced without PopulationChanged, by a population of the same size.
+	game.Population = popFromCells(golife.CellList{{50, 50}, {51, 50}, {52, 50}})
+	if cells := game.CellsInRect(golife.Cell{-10, -10}, golife.Cell{10, 10}); len(cells) != 0 {
+		t.Errorf("Cells from the old population: %v", cells)
+	}
+	// And edited without it, changing the size.
+	delete(game.Population, golife.Cell{50, 50})
+	if min_cell, max_cell := game.BoundingBox(); min_cell != (golife.Cell{51, 50}) || max_cell != (golife.Cell{52, 50}) {
+		t.Errorf("Unexpected bounding box %v-%v", min_cell, max_cell)
+	}
+	game.AddCell(golife.Cell{60, 60})
+	if count := game.CountInRect(everything_min, everything_max); count != 3 {
+		t.Errorf("Expected 3 cells, counted %d", count)
+	}
+}
+
+func TestFillsAreOneEvent(t *testing.T) {
+	game := golife.NewGame()
+	events := make([]golife.GameEvent, 0)
+	game.AddHook(func(game *golife.Game, event golife.GameEvent) {
+		events = append(events, event)
+	})
+	game.EnableJournal(-1)
+
+	game.FillRect(golife.Cell{0, 0}, golife.Cell{9, 9})
+	game.ClearRect(golife.Cell{0, 0}, golife.Cell{4, 9})
+	game.RandomFillRect(golife.Cell{0, 0}, golife.Cell{9, 9}, 0.5, rand.New(rand.NewSource(1)))
+	before := game.Size()
+	game.FillSoup(golife.Cell{5, 0}, golife.Cell{20, 15}, 0.5, golife.C1, "seed")
+	if len(events) != 4 {
+		t.Fatalf("Expected an event per fill, got %d", len(events))
+	}
+	if len(events[0].Births) != 100 || len(events[1].Deaths) != 50 || len(events[2].Deaths) != 0 {
+		t.Errorf("Unexpected fill events %d, %d and %d", len(events[0].Births), len(events[1].Deaths), len(events[2].Deaths))
+	}
+	if soup := events[3]; before+len(soup.Births)-len(soup.Deaths) != game.Size() {
+		t.Errorf("Soup event doesn't account for the change from %d to %d cells", before, game.Size())
+	}
+
+	for range 4 {
+		game.Undo()
+	}
+	if game.Size() != 0 || len(events) != 8 {
+		t.Errorf("Expected four undos back to empty, %d cells and %d events", game.Size(), len(events))
+	}
+}
@@ -0,0 +1,116 @@
+package golife
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"math"
+	"slices"
+)
+
+var everywhere_min, everywhere_max = Cell{math.MinInt64, math.MinInt64}, Cell{math.MaxInt64, math.MaxInt64}
+
+// The sorted iterators hold one band of rows at a time, with no more than
+// this fraction of the cells in it, or min_sorted_band cells if that's more
+// (or a single row, if it's bigger still).
+const (
+	sorted_band_divisor = 16
+	min_sorted_band     = 4096
+)
+
+func compareCells(a, b Cell) int {
+	if a.Y != b.Y {
+		return cmp.Compare(a.Y, b.Y)
+	}
+	return cmp.Compare(a.X, b.X)
+}
+
+// sortedBands calls f with the cells in the rectangle a band of rows at a
+// time, top to bottom, each band sorted, until f returns false.  Counting
+// the cells in each row first lets it size the bands, then each band takes
+// another pass over the population to collect.
+func (pop Population) sortedBands(min_cell, max_cell Cell, f func(band []Cell) bool) {
+	inside := func(cell Cell) bool {
+		return cell.X >= min_cell.X && cell.X <= max_cell.X && cell.Y >= min_cell.Y && cell.Y <= max_cell.Y
+	}
+	counts := make(map[Coord]int)
+	total := 0
+	for cell, present := range pop {
+		if present && inside(cell) {
+			counts[cell.Y] += 1
+			total += 1
+		}
+	}
+	ys := slices.Sorted(maps.Keys(counts))
+	budget := max(min_sorted_band, total/sorted_band_divisor)
+
+	for start := 0; start < len(ys); {
+		end, size := start+1, counts[ys[start]]
+		for end < len(ys) && size+counts[ys[end]] <= budget {
+			size += counts[ys[end]]
+			end += 1
+		}
+		first, last := ys[start], ys[end-1]
+		band := make([]Cell, 0, size)
+		for cell, present := range pop {
+			if present && inside(cell) && cell.Y >= first && cell.Y <= last {
+				band = append(band, cell)
+			}
+		}
+		slices.SortFunc(band, compareCells)
+		if !f(band) {
+			return
+		}
+		start = end
+	}
+}
+
+func (pop Population) rowsInRect(min_cell, max_cell Cell) iter.Seq2[Coord, []Cell] {
+	return func(yield func(Coord, []Cell) bool) {
+		pop.sortedBands(min_cell, max_cell, func(band []Cell) bool {
+			for len(band) > 0 {
+				end := 1
+				for end < len(band) && band[end].Y == band[0].Y {
+					end += 1
+				}
+				if !yield(band[0].Y, band[:end:end]) {
+					return false
+				}
+				band = band[end:]
+			}
+			return true
+		})
+	}
+}
+
+// All yields every cell in the same order as sorting a CellList, by row and
+// then by column, so that anything built from it comes out the same way
+// every time.  Rather than sorting a copy of the whole population, it
+// sorts a band of rows at a time, holding at most a sixteenth of the cells
+// (or a few thousand, for small populations) at once, at the cost of a pass
+// over the population per band.
+func (pop Population) All() iter.Seq[Cell] {
+	return pop.InRect(everywhere_min, everywhere_max)
+}
+
+// Rows yields each row that has live cells in it, top to bottom, with the
+// row's cells sorted left to right, working a band at a time like All.
+func (pop Population) Rows() iter.Seq2[Coord, []Cell] {
+	return pop.rowsInRect(everywhere_min, everywhere_max)
+}
+
+// InRect yields the cells within the rectangle from min_cell to max_cell
+// inclusive, in the same order as All.  Only the cells inside the rectangle
+// are collected into bands.
+func (pop Population) InRect(min_cell, max_cell Cell) iter.Seq[Cell] {
+	return func(yield func(Cell) bool) {
+		pop.sortedBands(min_cell, max_cell, func(band []Cell) bool {
+			for _, cell := range band {
+				if !yield(cell) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
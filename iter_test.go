@@ -0,0 +1,87 @@
+package golife_test
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestAllIsSorted(t *testing.T) {
+	pop := golife.SoupFromSeed("k_test0")
+	expected := make(golife.CellList, 0, pop.Size())
+	for cell := range pop {
+		expected = append(expected, cell)
+	}
+	sort.Sort(expected)
+
+	got := slices.Collect(pop.All())
+	if !slices.Equal(got, []golife.Cell(expected)) {
+		t.Errorf("All out of order:\n%v\nexpected\n%v", got, expected)
+	}
+}
+
+func TestRows(t *testing.T) {
+	pop := popFromCells(golife.CellList{{2, 1}, {0, 1}, {5, -3}, {1, 1}})
+	ys := make([]golife.Coord, 0)
+	for y, row := range pop.Rows() {
+		ys = append(ys, y)
+		if y == 1 && !slices.Equal(row, []golife.Cell{{0, 1}, {1, 1}, {2, 1}}) {
+			t.Errorf("Row 1 unsorted: %v", row)
+		}
+	}
+	if !slices.Equal(ys, []golife.Coord{-3, 1}) {
+		t.Errorf("Unexpected rows %v", ys)
+	}
+}
+
+func TestInRect(t *testing.T) {
+	pop := popFromCells(golife.CellList{{0, 0}, {1, 1}, {2, 2}, {3, 3}})
+	got := slices.Collect(pop.InRect(golife.Cell{1, 0}, golife.Cell{2, 5}))
+	if !slices.Equal(got, []golife.Cell{{1, 1}, {2, 2}}) {
+		t.Errorf("Unexpected cells in rect %v", got)
+	}
+
+	for cell := range pop.InRect(golife.Cell{0, 0}, golife.Cell{3, 3}) {
+		if cell != (golife.Cell{0, 0}) {
+			t.Errorf("Iteration didn't start at the first cell, got %v", cell)
+		}
+		break
+	}
+}
+
+func TestAllInBands(t *testing.T) {
+	// Enough cells that they are sorted in several bands, with one row too
+	// long to share a band with anything.
+	pop := make(golife.Population)
+	for y := golife.Coord(0); y < 200; y++ {
+		for x := golife.Coord(0); x < 200; x++ {
+			if (x*7+y*13)%3 != 0 {
+				pop[golife.Cell{X: x, Y: y}] = true
+			}
+		}
+	}
+	for x := golife.Coord(0); x < 6000; x++ {
+		pop[golife.Cell{X: -x, Y: 100}] = true
+	}
+	expected := make(golife.CellList, 0, pop.Size())
+	for cell := range pop {
+		expected = append(expected, cell)
+	}
+	sort.Sort(expected)
+
+	if got := slices.Collect(pop.All()); !slices.Equal(got, []golife.Cell(expected)) {
+		t.Errorf("All out of order across bands")
+	}
+	count := 0
+	for y, row := range pop.Rows() {
+		if y != golife.Coord(count) || !slices.IsSortedFunc(row, func(a, b golife.Cell) int { return int(a.X - b.X) }) {
+			t.Fatalf("Row %d out of order", y)
+		}
+		count += 1
+	}
+	if count != 200 {
+		t.Errorf("Expected 200 rows, got %d", count)
+	}
+}
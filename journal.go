@@ -0,0 +1,305 @@
+package golife
+
+import (
+	"io"
+)
+
+type journalKind int
+
+const (
+	editOp journalKind = iota
+	stepOp
+	backOp
+	jumpOp
+)
+
+// journalOp is a single change.  For edits, cell and alive are the cell
+// changed and what it was set to.  For steps, births and deaths are the
+// cells born and died moving forward, and for moving back with Previous
+// and jumps, the cells that appeared and disappeared.  Jumps also keep the
+// generations they went between and the timeline as it was beforehand.
+type journalOp struct {
+	kind           journalKind
+	cell           Cell
+	alive          bool
+	births, deaths []Cell
+	from, to       int
+	timeline       *timeline
+}
+
+type journalEntry struct {
+	name string
+	ops  []journalOp
+}
+
+// journal records cell edits made through the Game methods and generation
+// steps made by Next, in the order they happened, so they can be undone and
+// redone together.
+type journal struct {
+	limit     int
+	undo      []journalEntry
+	redo      []journalEntry
+	open      *journalEntry
+	depth     int
+	replaying bool
+}
+
+// EnableJournal starts recording edits and steps for Undo and Redo, keeping
+// up to limit entries (or any number if limit is negative).  A limit of 0
+// turns the journal off.  Moving back with Previous is recorded too, so
+// undoing it brings back the generation it left, edits and all, and each
+// SeekTo or GoTo is recorded as one entry.  Undoing a GoTo that had to jump
+// puts the population and generation back, but not the history from before
+// the jump.  Edits made directly to Population aren't seen.
+func (game *Game) EnableJournal(limit int) {
+	if limit == 0 {
+		game.journal = nil
+		return
+	}
+	var j journal
+	j.limit = limit
+	j.undo = make([]journalEntry, 0, 10)
+	j.redo = make([]journalEntry, 0, 10)
+	game.journal = &j
+}
+
+// BeginEdit groups everything up to the matching EndEdit into a single named
+// entry that is undone and redone as a whole.  Calls may be nested, only the
+// outermost name is kept.
+func (game *Game) BeginEdit(name string) {
+	j := game.journal
+	if j == nil {
+		return
+	}
+	if j.depth == 0 {
+		j.open = &journalEntry{name: name}
+	}
+	j.depth += 1
+}
+
+func (game *Game) EndEdit() {
+	j := game.journal
+	if j == nil || j.depth == 0 {
+		return
+	}
+	j.depth -= 1
+	if j.depth == 0 {
+		entry := j.open
+		j.open = nil
+		if len(entry.ops) > 0 {
+			j.push(*entry)
+		}
+	}
+}
+
+func (j *journal) push(entry journalEntry) {
+	j.undo = append(j.undo, entry)
+	if j.limit > 0 && len(j.undo) > j.limit {
+		j.undo = append(j.undo[:0], j.undo[len(j.undo)-j.limit:]...)
+	}
+	if !j.replaying {
+		j.redo = j.redo[:0]
+	}
+}
+
+func (game *Game) journalOp(name string, op journalOp) {
+	j := game.journal
+	if j == nil {
+		return
+	}
+	if j.open != nil {
+		j.open.ops = append(j.open.ops, op)
+	} else {
+		j.push(journalEntry{name: name, ops: []journalOp{op}})
+	}
+}
+
+func (game *Game) journalCell(cell Cell, alive bool) {
+	if game.journal != nil && game.Population[cell] != alive {
+		game.journalOp("edit", journalOp{kind: editOp, cell: cell, alive: alive})
+	}
+}
+
+func (game *Game) journalStep(delta generationDelta) {
+	if game.journal != nil {
+		game.journalOp("step", journalOp{kind: stepOp, births: delta.births, deaths: delta.deaths})
+	}
+}
+
+func (game *Game) journalBack(births, deaths []Cell) {
+	if game.journal != nil {
+		game.journalOp("back", journalOp{kind: backOp, births: births, deaths: deaths})
+	}
+}
+
+// UndoName returns the name of the entry Undo would undo, or an empty string
+// if there is nothing to undo.
+func (game *Game) UndoName() string {
+	if game.journal == nil || len(game.journal.undo) == 0 {
+		return ""
+	}
+	return game.journal.undo[len(game.journal.undo)-1].name
+}
+
+func (game *Game) RedoName() string {
+	if game.journal == nil || len(game.journal.redo) == 0 {
+		return ""
+	}
+	return game.journal.redo[len(game.journal.redo)-1].name
+}
+
+// Undo reverts the most recent journal entry.  Undoing a step also drops it
+// from the history, as Previous would.
+func (game *Game) Undo() error {
+	j := game.journal
+	if j == nil || len(j.undo) == 0 {
+		return io.EOF
+	}
+	game.closeOpenEdit()
+
+	entry := j.undo[len(j.undo)-1]
+	j.undo = j.undo[:len(j.undo)-1]
+	for i := len(entry.ops) - 1; i >= 0; i-- {
+		op := entry.ops[i]
+		switch op.kind {
+		case editOp:
+			// A run of edits is put back as one, like it was made.
+			first := i
+			for first > 0 && entry.ops[first-1].kind == editOp {
+				first -= 1
+			}
+			births, deaths := netEdits(entry.ops[first:i+1], true)
+			game.changeCells(births, deaths, false)
+			i = first
+		case stepOp:
+			for _, cell := range op.births {
+				delete(game.Population, cell)
+			}
+			game.Population.Add(op.deaths)
+			game.Generation -= 1
+			game.rewindTimeline()
+			game.updateIndexDelta(op.deaths, op.births)
+			game.dropHistoryStep()
+			game.resetStatus()
+			game.fire(PreviousEvent, game.Generation+1, op.deaths, op.births)
+		case backOp:
+			// Going forward again to the generation Previous left, which
+			// may have been edited, so it's treated as an edit there.
+			next := game.Population.copy()
+			for _, cell := range op.births {
+				delete(next, cell)
+			}
+			next.Add(op.deaths)
+			game.advance(next, generationDelta{births: op.deaths, deaths: op.births})
+			game.resetStatus()
+			game.editTimeline()
+			game.fire(NextEvent, game.Generation-1, op.deaths, op.births)
+		case jumpOp:
+			for _, cell := range op.births {
+				delete(game.Population, cell)
+			}
+			game.Population.Add(op.deaths)
+			game.Generation = op.from
+			game.timeline = op.timeline
+			game.invalidateIndex()
+			game.resetHistory()
+			game.fire(JumpEvent, op.to, nil, nil)
+		}
+	}
+	j.redo = append(j.redo, entry)
+	return nil
+}
+
+// Redo re-applies the most recently undone entry.  Steps are re-run with
+// Next so the history is rebuilt along with them.
+func (game *Game) Redo() error {
+	j := game.journal
+	if j == nil || len(j.redo) == 0 {
+		return io.EOF
+	}
+	game.closeOpenEdit()
+
+	entry := j.redo[len(j.redo)-1]
+	j.redo = j.redo[:len(j.redo)-1]
+	j.replaying = true
+	game.BeginEdit(entry.name)
+	for i := 0; i < len(entry.ops); i++ {
+		op := entry.ops[i]
+		switch {
+		case op.kind == stepOp:
+			game.Next()
+		case op.kind == backOp:
+			game.Previous()
+		case op.kind == jumpOp:
+			pop := game.Population.copy()
+			for _, cell := range op.deaths {
+				delete(pop, cell)
+			}
+			pop.Add(op.births)
+			game.jumpTo(pop, op.to)
+		default:
+			last := i
+			for last+1 < len(entry.ops) && entry.ops[last+1].kind == editOp {
+				last += 1
+			}
+			births, deaths := netEdits(entry.ops[i:last+1], false)
+			game.changeCells(births, deaths, true)
+			i = last
+		}
+	}
+	game.EndEdit()
+	j.replaying = false
+	return nil
+}
+
+func (game *Game) closeOpenEdit() {
+	for game.journal.depth > 0 {
+		game.EndEdit()
+	}
+}
+
+// netEdits returns the cells a run of edit ops leaves live and dead, or
+// those undoing it does, in the order they're first edited.
+func netEdits(ops []journalOp, undo bool) ([]Cell, []Cell) {
+	final := make(map[Cell]bool)
+	order := make([]Cell, 0, len(ops))
+	note := func(op journalOp) {
+		if _, seen := final[op.cell]; !seen {
+			order = append(order, op.cell)
+		}
+		final[op.cell] = op.alive != undo
+	}
+	if undo {
+		for i := len(ops) - 1; i >= 0; i-- {
+			note(ops[i])
+		}
+	} else {
+		for _, op := range ops {
+			note(op)
+		}
+	}
+
+	births, deaths := make([]Cell, 0), make([]Cell, 0)
+	for _, cell := range order {
+		if final[cell] {
+			births = append(births, cell)
+		} else {
+			deaths = append(deaths, cell)
+		}
+	}
+	return births, deaths
+}
+
+func (game *Game) dropHistoryStep() {
+	game.saveEditedDelta()
+	if game.deltas != nil {
+		if len(game.deltas.deltas) > 0 {
+			game.deltas.deltas = game.deltas.deltas[:len(game.deltas.deltas)-1]
+			delete(game.deltas.keyframes, game.Generation+1)
+		} else {
+			game.deltas = newDeltaHistory(game.deltas.interval, game.Population, game.Generation)
+		}
+	} else if len(game.History) > 0 {
+		game.History = game.History[:len(game.History)-1]
+	}
+}
@@ -0,0 +1,199 @@
+package golife_test
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestUndoRedoEdits(t *testing.T) {
+	game := golife.NewGame()
+	game.EnableJournal(-1)
+
+	game.AddCell(golife.Cell{0, 0})
+	game.BeginEdit("draw blinker")
+	game.AddCells(testPattern)
+	game.RemoveCell(golife.Cell{5, 5})
+	game.EndEdit()
+
+	if name := game.UndoName(); name != "draw blinker" {
+		t.Errorf("Expected to undo \"draw blinker\", got %q", name)
+	}
+	if err := game.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if game.Size() != 1 || !game.HasCell(golife.Cell{0, 0}) {
+		t.Errorf("Undo didn't restore single cell, population is %v", game.Population)
+	}
+
+	if err := game.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if match, errmsg := samePop(popFromCells(testPattern), game.Population); !match {
+		t.Errorf("Redo didn't restore blinker: %s", errmsg)
+	}
+
+	game.Undo()
+	game.Undo()
+	if game.Size() != 0 {
+		t.Errorf("Expected empty population, got %v", game.Population)
+	}
+	if err := game.Undo(); err == nil {
+		t.Error("Undo past the start of the journal")
+	}
+}
+
+func TestUndoInterleavedSteps(t *testing.T) {
+	game := golife.NewGame()
+	game.SetHistorySize(-1)
+	game.EnableJournal(-1)
+
+	game.AddCells(testPattern)
+	game.Next()
+	game.AddCell(golife.Cell{10, 10})
+	game.Next()
+
+	game.Undo()
+	game.Undo()
+	if game.Generation != 1 || game.Size() != 3 {
+		t.Errorf("Expected vertical blinker at generation 1, got %v at %d", game.Population, game.Generation)
+	}
+	if match, errmsg := samePop(popFromCells(testPatternStep), game.Population); !match {
+		t.Errorf("Edit not undone: %s", errmsg)
+	}
+	if len(game.History) != 1 {
+		t.Errorf("Undoing a step should drop it from history, have %d entries", len(game.History))
+	}
+
+	game.Redo()
+	game.Redo()
+	if game.Generation != 2 || game.HasCell(golife.Cell{10, 10}) || game.Size() != 3 {
+		t.Errorf("Redo didn't replay edit and step: %v at %d", game.Population, game.Generation)
+	}
+
+	game.Undo()
+	game.AddCell(golife.Cell{20, 20})
+	if err := game.Redo(); err == nil {
+		t.Error("A new edit should clear the redo stack")
+	}
+}
+
+func TestUndoPrevious(t *testing.T) {
+	for _, interval := range []int{0, 4} {
+		game := golife.NewGame()
+		game.SetHistorySize(-1)
+		game.SetKeyframeInterval(interval)
+		game.EnableJournal(-1)
+
+		game.AddCells(testPattern)
+		game.Next()
+		game.AddCell(golife.Cell{10, 10})
+		edited := popFromCells(slices.Collect(game.Population.All()))
+		if err := game.Previous(); err != nil {
+			t.Fatal(err)
+		}
+
+		if name := game.UndoName(); name != "back" {
+			t.Errorf("Expected to undo going back, got %q", name)
+		}
+		if err := game.Undo(); err != nil {
+			t.Fatal(err)
+		}
+		if match, errmsg := samePop(edited, game.Population); !match || game.Generation != 1 {
+			t.Errorf("Undo didn't bring back the edit at generation 1, at %d: %s", game.Generation, errmsg)
+		}
+
+		// The edit is still in the journal, as is the step before it.
+		game.Undo()
+		game.Undo()
+		if match, errmsg := samePop(popFromCells(testPattern), game.Population); !match || game.Generation != 0 {
+			t.Errorf("Expected horizontal blinker at generation 0, at %d: %s", game.Generation, errmsg)
+		}
+
+		game.Redo()
+		game.Redo()
+		game.Redo()
+		if match, errmsg := samePop(popFromCells(testPattern), game.Population); !match || game.Generation != 0 {
+			t.Errorf("Redo didn't go back again, at %d: %s", game.Generation, errmsg)
+		}
+		if err := game.Undo(); err != nil || !game.HasCell(golife.Cell{10, 10}) {
+			t.Errorf("Edit lost on the second undo: %v", err)
+		}
+	}
+}
+
+// TestJournalRoundTrip makes random edits and moves, then checks that
+// undoing them all passes back through every state the game was in, and
+// redoing them passes forward through them again.
+func TestJournalRoundTrip(t *testing.T) {
+	type state struct {
+		generation int
+		pop        golife.Population
+	}
+
+	for seed := range int64(200) {
+		rng := rand.New(rand.NewSource(seed))
+		game := golife.NewGame()
+		game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}, {1, 2}, {2, 3}, {0, 4}})
+		game.SetHistorySize([]int{-1, 4}[seed%2])
+		game.SetKeyframeInterval([]int{0, 3}[seed/2%2])
+		game.SetCycleDetection(4)
+		game.SetTimeline(3, 5)
+		game.EnableJournal(-1)
+
+		current := func() state {
+			return state{game.Generation, popFromCells(slices.Collect(game.Population.All()))}
+		}
+		states := []state{current()}
+		for range 40 {
+			switch rng.Intn(5) {
+			case 0:
+				game.Next()
+			case 1:
+				cell := golife.Cell{golife.Coord(rng.Intn(6)), golife.Coord(rng.Intn(6))}
+				if game.HasCell(cell) {
+					game.RemoveCell(cell)
+				} else {
+					game.AddCell(cell)
+				}
+				states = append(states, current())
+				continue
+			case 2:
+				game.Previous()
+			case 3:
+				game.SeekTo(rng.Intn(game.Generation + 10))
+			case 4:
+				game.GoTo(rng.Intn(game.Generation + 30))
+			}
+			if game.Generation != states[len(states)-1].generation {
+				states = append(states, current())
+			}
+		}
+
+		check := func(want state, doing string) {
+			if game.Generation != want.generation {
+				t.Fatalf("Seed %d: at generation %d after %s, expected %d", seed, game.Generation, doing, want.generation)
+			}
+			if match, errmsg := samePop(want.pop, game.Population); !match {
+				t.Fatalf("Seed %d: wrong population at generation %d after %s: %s", seed, game.Generation, doing, errmsg)
+			}
+		}
+		for i := len(states) - 2; i >= 0; i-- {
+			if err := game.Undo(); err != nil {
+				t.Fatalf("Seed %d: undo %d failed: %v", seed, len(states)-1-i, err)
+			}
+			check(states[i], "undo")
+		}
+		if err := game.Undo(); err == nil {
+			t.Fatalf("Seed %d: more entries in the journal than changes made", seed)
+		}
+		for i := 1; i < len(states); i++ {
+			if err := game.Redo(); err != nil {
+				t.Fatalf("Seed %d: redo %d failed: %v", seed, i, err)
+			}
+			check(states[i], "redo")
+		}
+	}
+}
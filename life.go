@@ -8,7 +8,6 @@ import (
 	"log"
 	"math"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
 	"unicode"
@@ -42,8 +41,61 @@ func (pop Population) Size() int {
 	return len(pop)
 }
 
+// Hash returns a value that depends only on which cells are alive, so two
+// populations with the same cells hash the same regardless of map order.
+func (pop Population) Hash() uint64 {
+	var hash uint64
+	for cell, present := range pop {
+		if present {
+			hash += mixCell(cell)
+		}
+	}
+	return hash
+}
+
+func mixCell(cell Cell) uint64 {
+	z := uint64(cell.X)*0x9e3779b97f4a7c15 ^ uint64(cell.Y)
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
 func (current Population) Step() Population {
 	nextgen := make(Population, len(current))
+	for cell, count := range current.neighborCounts() {
+		if count == 3 || count == 2 && current[cell] {
+			nextgen[cell] = true
+		}
+	}
+
+	return nextgen
+}
+
+// StepChanges calculates the next generation like Step, and also returns
+// the cells that were born and the cells that died along the way.
+func (current Population) StepChanges() (Population, []Cell, []Cell) {
+	nextgen := make(Population, len(current))
+	births := make([]Cell, 0)
+	for cell, count := range current.neighborCounts() {
+		if count == 3 || count == 2 && current[cell] {
+			nextgen[cell] = true
+			if !current[cell] {
+				births = append(births, cell)
+			}
+		}
+	}
+
+	deaths := make([]Cell, 0)
+	for cell, present := range current {
+		if present && !nextgen[cell] {
+			deaths = append(deaths, cell)
+		}
+	}
+
+	return nextgen, births, deaths
+}
+
+func (current Population) neighborCounts() map[Cell]int8 {
 	neighbor_count := make(map[Cell]int8, len(current)*4)
 	for cell := range current {
 		x, y := cell.X, cell.Y
@@ -57,16 +109,15 @@ func (current Population) Step() Population {
 		neighbor_count[Cell{x + 1, y + 1}]++
 	}
 
-	for cell, count := range neighbor_count {
-		if count == 3 || count == 2 && current[cell] {
-			nextgen[cell] = true
-		}
-	}
-
-	return nextgen
+	return neighbor_count
 }
 
 
+// Game is a population along with its history and metadata.  Population
+// may be edited or replaced directly, but PopulationChanged must be called
+// afterwards; the Game methods that change it keep everything up to date
+// themselves.  Rule is the rule given in the header of the file the game was
+// read from, if any.
 type Game struct {
 	Filename    string
 	Population  Population
@@ -74,8 +125,20 @@ type Game struct {
 	HistorySize int
 	Name        string
 	Author      string
+	Rule        string
 	Comments    []string
 	Generation  int
+	cycleLimit  int
+	hashes      []uint64
+	status      Status
+	period      int
+	deltas      *deltaHistory
+	timeline    *timeline
+	journal     *journal
+	hooks       []hookEntry
+	indexed     bool
+	index       *tileIndex
+	checkpoints *checkpointer
 }
 
 func NewGame() *Game {
@@ -98,12 +161,26 @@ func (game *Game) Copy() *Game {
 	for index := range game.Comments {
 		newgame.Comments[index] = game.Comments[index]
 	}
+	newgame.hashes = append([]uint64(nil), game.hashes...)
+	if game.deltas != nil {
+		newgame.deltas = newDeltaHistory(game.deltas.interval, newgame.Population, newgame.Generation)
+	}
+	if game.timeline != nil {
+		newgame.SetTimeline(game.timeline.interval, game.timeline.limit)
+	}
+	if game.journal != nil {
+		newgame.EnableJournal(game.journal.limit)
+	}
+	newgame.hooks = nil
+	newgame.index = nil
+	newgame.checkpoints = nil
 	// I'm explicitly not copying the history, that may need to be revisited later
 	return &newgame
 }
 
 func (game *Game) Init() {
 	game.Population = make(Population)
+	game.invalidateIndex()
 	game.Comments = make([]string, 0, 10)
 	game.History = make([]Population, 0, 10)
 }
@@ -120,27 +197,134 @@ func (game *Game) SetHistorySize(size int) {
 	} else if size > 0 && len(game.History) > size {
 		game.History = game.History[len(game.History)-size:]
 	}
+	if game.deltas != nil && size > 0 {
+		game.deltas.trim(size)
+	}
 
 	game.HistorySize = size
 }
 
 func (game *Game) AddCell(cell Cell) {
+	if game.Population[cell] {
+		return
+	}
+	game.journalCell(cell, true)
 	game.Population[cell] = true
+	game.updateIndex(cell, true)
+	game.edited()
+	game.fire(EditEvent, game.Generation, []Cell{cell}, nil)
 }
 
 func (game *Game) AddCells(cells []Cell) {
+	if game.journal != nil || len(game.hooks) > 0 {
+		game.BeginEdit("edit")
+		game.changeCells(cells, nil, true)
+		game.EndEdit()
+		return
+	}
 	game.Population.Add(cells)
+	game.invalidateIndex()
+	game.edited()
+}
+
+// changeCells makes the births live and the deaths dead as a single edit,
+// with one EditEvent for the cells that actually changed, journaling them
+// if record is set.
+func (game *Game) changeCells(births, deaths []Cell, record bool) {
+	born := make([]Cell, 0, len(births))
+	died := make([]Cell, 0, len(deaths))
+	for _, cell := range deaths {
+		if game.Population[cell] {
+			if record {
+				game.journalCell(cell, false)
+			}
+			delete(game.Population, cell)
+			game.updateIndex(cell, false)
+			died = append(died, cell)
+		}
+	}
+	for _, cell := range births {
+		if !game.Population[cell] {
+			if record {
+				game.journalCell(cell, true)
+			}
+			game.Population[cell] = true
+			game.updateIndex(cell, true)
+			born = append(born, cell)
+		}
+	}
+	if len(born) > 0 || len(died) > 0 {
+		game.edited()
+		game.fire(EditEvent, game.Generation, born, died)
+	}
 }
 
 func (game *Game) RemoveCell(cell Cell) {
+	if !game.Population[cell] {
+		return
+	}
+	game.journalCell(cell, false)
 	delete(game.Population, cell)
+	game.updateIndex(cell, false)
+	game.edited()
+	game.fire(EditEvent, game.Generation, nil, []Cell{cell})
+}
+
+// PopulationChanged must follow any direct edit to Population.  It treats
+// the change as an edit, dropping the spatial index and cycle detection.
+func (game *Game) PopulationChanged() {
+	game.invalidateIndex()
+	game.edited()
+}
+
+func (game *Game) edited() {
+	game.resetStatus()
+	game.editTimeline()
+	if game.deltas != nil {
+		game.deltas.dirty = true
+	}
 }
 
 func (game *Game) HasCell(cell Cell) bool {
 	return game.Population[cell]
 }
 
-func (game *Game) Next() {
+func (game *Game) Next() Status {
+	if game.cycleLimit > 0 && len(game.hashes) == 0 {
+		game.hashes = append(game.hashes, game.Population.Hash())
+	}
+	var next Population
+	var delta generationDelta
+	if game.journal != nil || game.deltas != nil || game.index != nil || len(game.hooks) > 0 {
+		next, delta.births, delta.deaths = game.Population.StepChanges()
+	} else {
+		next = game.Population.Step()
+	}
+	game.journalStep(delta)
+	game.advance(next, delta)
+	game.recordKeyframe()
+	status := game.updateStatus()
+	game.fire(NextEvent, game.Generation-1, delta.births, delta.deaths)
+	game.autoCheckpoint()
+	return status
+}
+
+// advance moves forward a generation to next, recording the current one in
+// the history.  delta must hold the cells born and died on the way.
+func (game *Game) advance(next Population, delta generationDelta) {
+	game.saveEditedKeyframe()
+	game.saveEditedDelta()
+	if game.deltas != nil {
+		game.recordDelta(delta, next)
+	} else {
+		game.recordHistory()
+	}
+	game.Population = next
+	game.Generation += 1
+	game.updateIndexDelta(delta.births, delta.deaths)
+}
+
+func (game *Game) recordHistory() {
 	if game.HistorySize != 0 {
 		if game.History == nil {
 			if game.HistorySize > 0 {
@@ -156,46 +340,96 @@ func (game *Game) Next() {
 	} else {
 		game.History = nil
 	}
-	game.Population = game.Population.Step()
-	game.Generation += 1
 }
 
 func (game *Game) Previous() error {
+	if game.deltas != nil {
+		delta, err := game.previousDelta()
+		if err == nil {
+			game.journalBack(delta.deaths, delta.births)
+			game.resetStatus()
+			game.fire(PreviousEvent, game.Generation+1, delta.deaths, delta.births)
+		}
+		return err
+	}
 	if game.HistorySize == 0 || len(game.History) == 0 {
 		return io.EOF
 	}
 
 	prevPop := game.History[len(game.History)-1]
 	game.History = game.History[:len(game.History)-1]
+	if len(game.hooks) > 0 || game.journal != nil {
+		delta := diffPopulations(game.Population, prevPop)
+		game.journalBack(delta.births, delta.deaths)
+		game.Population = prevPop
+		game.Generation -= 1
+		game.rewindTimeline()
+		game.updateIndexDelta(delta.births, delta.deaths)
+		game.resetStatus()
+		game.fire(PreviousEvent, game.Generation+1, delta.births, delta.deaths)
+		return nil
+	}
 	game.Population = prevPop
 	game.Generation -= 1
+	game.rewindTimeline()
+	game.invalidateIndex()
+	game.resetStatus()
 	return nil
 }
 
+// Load reads a pattern file in whichever registered format its name
+// suggests, or failing that, the format its contents look like.
 func Load(filepath string) (*Game, error) {
 	filereader, err := os.Open(filepath)
 	if err != nil {
 		return nil, err
 	}
 	defer filereader.Close()
+	bufreader := bufio.NewReader(filereader)
 	readerfunc := FindReader(filepath)
-	game, err := readerfunc(filereader)
+	if FormatFor(filepath) == "" {
+		head, _ := bufreader.Peek(sniff_length)
+		if format := SniffFormat(head); format != "" {
+			readerfunc = func(reader io.Reader) (*Game, error) {
+				return Read(reader, format)
+			}
+		}
+	}
+	game, err := readerfunc(bufreader)
 	if game != nil {
 		game.Filename = filepath
+		if err == nil {
+			fireLoadHooks(game)
+		}
 	}
 	return game, err
 }
 
+// Save writes the game to a file in the registered format its name
+// suggests.
+func (game *Game) Save(filepath string) error {
+	format := FormatFor(filepath)
+	if format == "" {
+		return errors.New("Unsupported file type")
+	}
+	fileWriter, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	err = game.Write(fileWriter, format)
+	closeErr := fileWriter.Close()
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
 func FindReader(filepath string) func(io.Reader) (*Game, error) {
-	if strings.HasSuffix(filepath, ".rle") || strings.HasSuffix(filepath, ".rle.txt") {
-		return ReadRLE
-	} else if strings.HasSuffix(filepath, ".life") || strings.HasSuffix(filepath, ".life.txt") {
-		return ReadLife
-	} else if strings.HasSuffix(filepath, ".cells") || strings.HasSuffix(filepath, ".cells.txt") {
-		return ReadCells
-	} else {
+	format, err := formatNamed(FormatFor(filepath))
+	if err != nil || format.reader == nil {
 		return UnknownFiletypeReader
 	}
+	return format.reader
 }
 
 func UnknownFiletypeReader(reader io.Reader) (*Game, error) {
@@ -311,6 +545,7 @@ func ReadRLE(reader io.Reader) (*Game, error) {
 	if strings.ToLower(rule) != "b3/s23" {
 		return nil, errors.New("Unable to handle rule " + rule)
 	}
+	g.Rule = rule
 
 	if !done {
 		log.Println("WARN: Did not get terminator at end of RLE file")
@@ -327,14 +562,14 @@ func ReadRLE(reader io.Reader) (*Game, error) {
 	return g, nil
 }
 
-func (population *Population) BoundingBox() (Cell, Cell) {
+func (population Population) BoundingBox() (Cell, Cell) {
 	var min_cell, max_cell Cell
 	min_cell.X = math.MaxInt64
 	min_cell.Y = math.MaxInt64
 	max_cell.X = math.MinInt64
 	max_cell.Y = math.MinInt64
 
-	for cell, present := range *population {
+	for cell, present := range population {
 		if present {
 			if cell.X < min_cell.X {
 				min_cell.X = cell.X
@@ -380,19 +615,13 @@ func (cell_list CellList) Len() int {
 func (game *Game) ExtractRLE() []EncodingPair {
 	rle := make([]EncodingPair, 0, 100)
 
-	cells := make(CellList, 0, len(game.Population))
-	for c, _ := range game.Population {
-		cells = append(cells, c)
-	}
-
 	min_cell, _ := game.Population.BoundingBox()
 
 	var last_x Coord = -1
 	var last_y Coord = 0
-	sort.Sort(cells)
-	for i := range cells {
-		rel_x := cells[i].X - min_cell.X
-		rel_y := cells[i].Y - min_cell.Y
+	for cell := range game.Population.All() {
+		rel_x := cell.X - min_cell.X
+		rel_y := cell.Y - min_cell.Y
 
 		switch {
 		case rel_y > last_y:
@@ -436,13 +665,13 @@ func (game *Game) WriteRLE(outfile io.Writer) error {
 
 	outwriter := bufio.NewWriter(outfile)
 	if game.Name != "" {
-		_, err := outwriter.WriteString(fmt.Sprintln("#N ", game.Name))
+		_, err := outwriter.WriteString("#N " + game.Name + "\n")
 		if err != nil {
 			return err
 		}
 	}
 	if game.Author != "" {
-		_, err := outwriter.WriteString(fmt.Sprintln("#O ", game.Author))
+		_, err := outwriter.WriteString("#O " + game.Author + "\n")
 		if err != nil {
 			return err
 		}
@@ -537,8 +766,25 @@ func ReadLife(reader io.Reader) (*Game, error) {
 	game := NewGame()
 	cells := make(CellList, 0, 100)
 
-	for j := range lines {
-		line := lines[j]
+	// Header lines don't count as rows, so a pattern written by WriteLife
+	// reads back where it was.
+	j := -1
+	for _, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		switch {
+		case strings.HasPrefix(line, "#Life"):
+			continue
+		case strings.HasPrefix(line, "#N "):
+			game.Name = strings.TrimPrefix(line, "#N ")
+			continue
+		case strings.HasPrefix(line, "#O "):
+			game.Author = strings.TrimPrefix(line, "#O ")
+			continue
+		case strings.HasPrefix(line, "#"):
+			game.Comments = append(game.Comments, line[1:])
+			continue
+		}
+		j += 1
 		chars := strings.Split(line, "")
 	lineloop:
 		for i := range chars {
@@ -612,3 +858,80 @@ func ReadCells(reader io.Reader) (*Game, error) {
 
 	return game, nil
 }
+
+// writeRows writes the pattern row by row over its bounding box, with each
+// line cut off after its last live cell.
+func (game *Game) writeRows(outwriter *bufio.Writer, alive, dead byte) error {
+	min_cell, _ := game.Population.BoundingBox()
+	last_y := min_cell.Y
+	for y, row := range game.Population.Rows() {
+		for ; last_y < y; last_y++ {
+			if _, err := outwriter.WriteString("\n"); err != nil {
+				return err
+			}
+		}
+		line := make([]byte, row[len(row)-1].X-min_cell.X+1)
+		for i := range line {
+			line[i] = dead
+		}
+		for _, cell := range row {
+			line[cell.X-min_cell.X] = alive
+		}
+		if _, err := outwriter.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		last_y = y + 1
+	}
+	return nil
+}
+
+// WriteCells writes the pattern in plaintext .cells format, with the name
+// and author as the first comment lines.
+func (game *Game) WriteCells(outfile io.Writer) error {
+	outwriter := bufio.NewWriter(outfile)
+	header := make([]string, 0, len(game.Comments)+2)
+	if game.Name != "" {
+		header = append(header, "Name: "+game.Name)
+	}
+	if game.Author != "" {
+		header = append(header, "Author: "+game.Author)
+	}
+	header = append(header, game.Comments...)
+	for _, comment := range header {
+		_, err := outwriter.WriteString("!" + strings.TrimSuffix(comment, "\n") + "\n")
+		if err != nil {
+			return err
+		}
+	}
+	if err := game.writeRows(outwriter, 'O', '.'); err != nil {
+		return err
+	}
+	return outwriter.Flush()
+}
+
+// WriteLife writes the pattern in the format read by ReadLife, which counts
+// any character other than a space as a live cell, so dead cells are
+// written as spaces.  It starts with a #Life 1.05 line, so the format can
+// be recognized, followed by #N and #O lines for the name and author.
+func (game *Game) WriteLife(outfile io.Writer) error {
+	outwriter := bufio.NewWriter(outfile)
+	header := make([]string, 0, len(game.Comments)+3)
+	header = append(header, "Life 1.05")
+	if game.Name != "" {
+		header = append(header, "N "+game.Name)
+	}
+	if game.Author != "" {
+		header = append(header, "O "+game.Author)
+	}
+	header = append(header, game.Comments...)
+	for _, comment := range header {
+		_, err := outwriter.WriteString("#" + strings.TrimSuffix(comment, "\n") + "\n")
+		if err != nil {
+			return err
+		}
+	}
+	if err := game.writeRows(outwriter, '*', ' '); err != nil {
+		return err
+	}
+	return outwriter.Flush()
+}
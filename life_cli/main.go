@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/pneumaticdeath/golife"
+	"github.com/pneumaticdeath/golife/examples"
 )
 
 func check(e error) {
@@ -29,7 +30,7 @@ func display(g *golife.Game) {
 		for y = min_cell.Y; y <= max_cell.Y; y++ {
 			cell_line := make([]string, width)
 			for x = min_cell.X; x <= max_cell.X; x++ {
-				if g.HasCell(golife.Cell{x, y}) {
+				if g.HasCell(golife.Cell{X: x, Y: y}) {
 					cell_line[int(x-min_cell.X)] = "*"
 				} else {
 					cell_line[int(x-min_cell.X)] = " "
@@ -49,6 +50,9 @@ func main() {
 	displayPtr := flag.Bool("display", false, "Display steps")
 	generationsPtr := flag.Int("generations", 100, "Number of generations to run")
 	pprofPtr := flag.String("pprof", "", "Write profiling output to file")
+	examplePtr := flag.String("example", "", "Built in example to start with, by title")
+	listExamplesPtr := flag.Bool("list-examples", false, "List the built in examples")
+	interactivePtr := flag.Bool("interactive", false, "Play the pattern in an interactive terminal viewer")
 
 	flag.Parse()
 
@@ -63,16 +67,38 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
+	if *listExamplesPtr {
+		for _, e := range examples.ListExamples() {
+			fmt.Printf("%-16s %s\n", e.Category, e.Title)
+		}
+		return
+	}
+
 	var err error
 	if *inputfilePtr != "" {
 		g, err = golife.Load(*inputfilePtr)
 		check(err)
+	} else if *examplePtr != "" {
+		for _, e := range examples.ListExamples() {
+			if strings.EqualFold(e.Title, *examplePtr) {
+				g = examples.LoadExample(e)
+				break
+			}
+		}
+		if g == nil {
+			log.Fatalf("No example titled %q, try -list-examples", *examplePtr)
+		}
 	} else {
-		cells := []golife.Cell{{0, 0}, {0, 1}, {0, 2}, {1, 0}, {2, 1}}
+		cells := []golife.Cell{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 0}, {X: 2, Y: 1}}
 		g = golife.NewGame()
 		g.AddCells(cells)
 	}
 
+	if *interactivePtr {
+		check(runTUI(g))
+		return
+	}
+
 	for i := 0; i < *generationsPtr && g.Population.Size() > 0; i++ {
 		if *displayPtr {
 			display(g)
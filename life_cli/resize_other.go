@@ -0,0 +1,11 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+)
+
+// resizeSignals is empty where there's no signal for a terminal resize, so
+// the size is only read at startup.
+var resizeSignals = []os.Signal{}
@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignals are sent when the terminal changes size.
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+const tui_help = "space play/pause  n step  b back  +/- speed  arrows/hjkl pan  z/x zoom  f fit  m mode  q quit"
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func terminalSize() (int, int) {
+	size, err := stty("size")
+	var rows, cols int
+	if err == nil {
+		fmt.Sscan(size, &rows, &cols)
+	}
+	if rows <= 0 || cols <= 0 {
+		return 24, 80
+	}
+	return rows, cols
+}
+
+type view struct {
+	center golife.Cell
+	scale  int
+	mode   golife.TerminalMode
+}
+
+func (v *view) rect(rows, cols int) (golife.Cell, golife.Cell) {
+	dx, dy := 1, 2
+	if v.mode == golife.Braille {
+		dx, dy = 2, 4
+	}
+	half_w := golife.Coord(cols * dx * v.scale / 2)
+	half_h := golife.Coord(rows * dy * v.scale / 2)
+	return golife.Cell{X: v.center.X - half_w, Y: v.center.Y - half_h},
+		golife.Cell{X: v.center.X + half_w - 1, Y: v.center.Y + half_h - 1}
+}
+
+func (v *view) fit(pop golife.Population, rows, cols int) {
+	min_cell, max_cell := pop.BoundingBox()
+	if min_cell.X > max_cell.X {
+		return
+	}
+	v.center = golife.Cell{X: (min_cell.X + max_cell.X + 1) / 2, Y: (min_cell.Y + max_cell.Y + 1) / 2}
+	opts := golife.TerminalOptions{Mode: v.mode, Width: cols, Height: rows}
+	v.scale = opts.FitScale(min_cell, max_cell)
+}
+
+func readKeys(keys chan<- string) {
+	buf := make([]byte, 16)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- string(buf[:n])
+	}
+}
+
+func draw(g *golife.SafeGame, runner *golife.Runner, v *view, speed float64, rows, cols int) {
+	rows -= 2
+	snapshot := g.Snapshot()
+	min_cell, max_cell := v.rect(rows, cols)
+
+	var frame bytes.Buffer
+	golife.WriteTerminal(&frame, snapshot.Population, min_cell, max_cell,
+		golife.TerminalOptions{Mode: v.mode, Width: cols, Height: rows, Scale: v.scale, Color: true})
+
+	state := "running"
+	if runner.Paused() {
+		state = "paused"
+	}
+	rate := "max"
+	if speed > 0 {
+		rate = fmt.Sprintf("%g/s", speed)
+	}
+
+	var screen bytes.Buffer
+	screen.WriteString("\x1b[H")
+	screen.Write(bytes.ReplaceAll(frame.Bytes(), []byte("\n"), []byte("\x1b[K\r\n")))
+	screen.WriteString("\x1b[J")
+	fmt.Fprintf(&screen, "\x1b[%d;1H\x1b[7m gen %d  pop %d  %s  speed %s  zoom 1:%d  center %d,%d \x1b[0m\x1b[K\r\n%s\x1b[K",
+		rows+1, snapshot.Generation, snapshot.Population.Size(), state, rate, v.scale, v.center.X, v.center.Y, tui_help)
+	os.Stdout.Write(screen.Bytes())
+}
+
+func runTUI(game *golife.Game) error {
+	saved, err := stty("-g")
+	if err != nil {
+		return fmt.Errorf("Unable to set up terminal: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return fmt.Errorf("Unable to set up terminal: %v", err)
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		stty(saved)
+	}()
+
+	game.SetHistorySize(10000)
+	game.SetKeyframeInterval(100)
+	g := golife.NewSafeGame(game)
+	runner := golife.NewRunner(g, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runner.Run(ctx)
+	go func() {
+		for range runner.Events() {
+		}
+	}()
+
+	speed := 10.0
+	runner.SetSpeed(speed)
+	// Running stty is too slow to do on every frame, so the size is read
+	// once and again whenever the terminal is resized.
+	resize := make(chan os.Signal, 1)
+	if len(resizeSignals) > 0 {
+		signal.Notify(resize, resizeSignals...)
+		defer signal.Stop(resize)
+	}
+	rows, cols := terminalSize()
+
+	var v view
+	v.scale = 1
+	v.fit(g.Snapshot().Population, rows-2, cols)
+
+	keys := make(chan string)
+	go readKeys(keys)
+	ticker := time.NewTicker(time.Second / 30)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			pan := golife.Coord(max(1, cols*v.scale/4))
+			switch key {
+			case "q", "\x03":
+				return nil
+			case " ":
+				if runner.Paused() {
+					runner.Resume()
+				} else {
+					runner.Pause()
+				}
+			case "n", ".":
+				runner.Pause()
+				runner.Step()
+			case "b", ",":
+				runner.Pause()
+				g.Previous()
+			case "+", "=":
+				if speed > 0 {
+					speed *= 2
+					if speed > 1000 {
+						speed = 0
+					}
+				}
+				runner.SetSpeed(speed)
+			case "-", "_":
+				if speed == 0 {
+					speed = 1024
+				} else if speed > 0.25 {
+					speed /= 2
+				}
+				runner.SetSpeed(speed)
+			case "h", "\x1b[D":
+				v.center.X -= pan
+			case "l", "\x1b[C":
+				v.center.X += pan
+			case "k", "\x1b[A":
+				v.center.Y -= pan
+			case "j", "\x1b[B":
+				v.center.Y += pan
+			case "z":
+				v.scale = max(1, v.scale/2)
+			case "x":
+				v.scale *= 2
+			case "f":
+				v.fit(g.Snapshot().Population, rows-2, cols)
+			case "m":
+				if v.mode == golife.HalfBlocks {
+					v.mode = golife.Braille
+				} else {
+					v.mode = golife.HalfBlocks
+				}
+				v.fit(g.Snapshot().Population, rows-2, cols)
+			}
+			draw(g, runner, &v, speed, rows, cols)
+		case <-resize:
+			rows, cols = terminalSize()
+			draw(g, runner, &v, speed, rows, cols)
+		case <-ticker.C:
+			draw(g, runner, &v, speed, rows, cols)
+		}
+	}
+}
@@ -85,6 +85,33 @@ func TestPopStep(t *testing.T) {
 	}
 }
 
+func TestStepChanges(t *testing.T) {
+	pop := make(golife.Population)
+	pop.Add(testPattern)
+
+	next, births, deaths := pop.StepChanges()
+	if match, errmsg := cmpPops(next, pop.Step()); !match {
+		t.Error(fmt.Sprintf("StepChanges doesn't agree with Step: %s", errmsg))
+	}
+
+	expectedBirths := make(golife.Population)
+	expectedBirths.Add(golife.CellList{{1, -1}, {1, 1}})
+	bornPop := make(golife.Population)
+	bornPop.Add(births)
+	if match, errmsg := cmpPops(bornPop, expectedBirths); !match || len(births) != 2 {
+		t.Error(fmt.Sprintf("Unexpected births %v: %s", births, errmsg))
+	}
+
+	for _, cell := range deaths {
+		if cell != (golife.Cell{0, 0}) && cell != (golife.Cell{2, 0}) {
+			t.Error(fmt.Sprintf("Unexpected death %v", cell))
+		}
+	}
+	if len(deaths) != 2 {
+		t.Error(fmt.Sprintf("Expected 2 deaths, got %v", deaths))
+	}
+}
+
 func TestCellsReader(t *testing.T) {
 	cellsReader := strings.NewReader("! foo\n.O.\nO.O\n.O.\n")
 	game, err := golife.ReadCells(cellsReader)
@@ -106,6 +133,67 @@ func TestCellsReader(t *testing.T) {
 	}
 }
 
+func TestCellsWriter(t *testing.T) {
+	game := golife.NewGame()
+	game.Name = "Tub"
+	game.AddCells(golife.CellList{{11, 5}, {10, 6}, {12, 6}, {11, 7}})
+
+	var out strings.Builder
+	if err := game.WriteCells(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "!Name: Tub\n.O\nO.O\n.O\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	readBack, err := golife.ReadCells(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPop := make(golife.Population)
+	expectedPop.Add(golife.CellList{{1, 0}, {0, 1}, {2, 1}, {1, 2}})
+	if matching, errmsg := cmpPops(expectedPop, readBack.Population); !matching {
+		t.Error(fmt.Sprintf("Unexpected population read back: %s", errmsg))
+	}
+}
+
+func TestLifeWriter(t *testing.T) {
+	game := golife.NewGame()
+	game.Name = "Not a blinker"
+	game.Author = "Someone"
+	game.Comments = append(game.Comments, " blinker")
+	game.AddCells(golife.CellList{{0, 0}, {0, 1}, {0, 3}})
+
+	var out strings.Builder
+	if err := game.WriteLife(&out); err != nil {
+		t.Fatal(err)
+	}
+	expected := "#Life 1.05\n#N Not a blinker\n#O Someone\n# blinker\n*\n*\n\n*\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	if format := golife.SniffFormat([]byte(out.String())); format != "life" {
+		t.Errorf("Written file sniffed as %q", format)
+	}
+	readBack, err := golife.ReadLife(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readBack.Name != game.Name || readBack.Author != game.Author {
+		t.Errorf("Expected name %q and author %q, got %q and %q", game.Name, game.Author, readBack.Name, readBack.Author)
+	}
+	if len(readBack.Comments) != 1 || readBack.Comments[0] != " blinker" {
+		t.Errorf("Comments not written, got %q", out.String())
+	}
+	expectedPop := make(golife.Population)
+	expectedPop.Add(golife.CellList{{0, 0}, {0, 1}, {0, 3}})
+	if matching, errmsg := cmpPops(expectedPop, readBack.Population); !matching {
+		t.Error(fmt.Sprintf("Unexpected population read back: %s", errmsg))
+	}
+}
+
 func TestAddRemoveCell(t *testing.T) {
 	cell := golife.Cell{1, 1}
 	game := golife.NewGame()
@@ -132,10 +220,13 @@ func TestLoaderForSupportedFiles(t *testing.T) {
 		t.Error("Loaded unsupported file rule without error")
 	}
 
-	_, err2 := golife.Load("examples/files/Simple/glider.rle")
+	glider, err2 := golife.Load("examples/files/Simple/glider.rle")
 
 	if err2 != nil {
-		t.Error(fmt.Sprintf("Error loading known good file glider.rle %s", err2))
+		t.Fatal(fmt.Sprintf("Error loading known good file glider.rle %s", err2))
+	}
+	if strings.ToLower(glider.Rule) != "b3/s23" {
+		t.Errorf("Expected the rule from the header, got %q", glider.Rule)
 	}
 }
 
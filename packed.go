@@ -0,0 +1,168 @@
+package golife
+
+import (
+	"errors"
+	"math"
+)
+
+// PackedCell holds a cell whose coordinates fit in an int32 in a single
+// 64 bit value, X in the high half and Y in the low half, so a population
+// keyed by them takes half the memory of one keyed by Cell.
+type PackedCell uint64
+
+type PackedPopulation map[PackedCell]bool
+
+// Cells is the API Population and PackedPopulation have in common, so code
+// that only adds, steps and looks at cells can be handed either one.
+// AddCells and StepCells never fail for a Population.
+type Cells interface {
+	Size() int
+	HasCell(cell Cell) bool
+	BoundingBox() (Cell, Cell)
+	AddCells(cells []Cell) error
+	StepCells() (Cells, error)
+}
+
+var _ Cells = Population(nil)
+var _ Cells = PackedPopulation(nil)
+
+func (pop Population) HasCell(cell Cell) bool {
+	return pop[cell]
+}
+
+func (pop Population) AddCells(cells []Cell) error {
+	pop.Add(cells)
+	return nil
+}
+
+func (current Population) StepCells() (Cells, error) {
+	return current.Step(), nil
+}
+
+var ErrCoordOverflow = errors.New("Coordinate outside of int32 range")
+
+func PackCell(cell Cell) (PackedCell, error) {
+	if cell.X < math.MinInt32 || cell.X > math.MaxInt32 || cell.Y < math.MinInt32 || cell.Y > math.MaxInt32 {
+		return 0, ErrCoordOverflow
+	}
+	return packXY(int32(cell.X), int32(cell.Y)), nil
+}
+
+func packXY(x, y int32) PackedCell {
+	return PackedCell(uint64(uint32(x))<<32 | uint64(uint32(y)))
+}
+
+func (packed PackedCell) xy() (int32, int32) {
+	return int32(uint32(packed >> 32)), int32(uint32(packed))
+}
+
+func (packed PackedCell) Cell() Cell {
+	x, y := packed.xy()
+	return Cell{Coord(x), Coord(y)}
+}
+
+// PackPopulation converts a population, failing if any of its cells are
+// outside the int32 range.
+func PackPopulation(pop Population) (PackedPopulation, error) {
+	packed := make(PackedPopulation, len(pop))
+	for cell, present := range pop {
+		if present {
+			p, err := PackCell(cell)
+			if err != nil {
+				return nil, err
+			}
+			packed[p] = true
+		}
+	}
+	return packed, nil
+}
+
+func (pop PackedPopulation) Population() Population {
+	unpacked := make(Population, len(pop))
+	for packed := range pop {
+		unpacked[packed.Cell()] = true
+	}
+	return unpacked
+}
+
+// Add adds new cells to the population.  If any of them are out of range,
+// none of them are added.
+func (pop PackedPopulation) Add(new_cells []Cell) error {
+	packed := make([]PackedCell, len(new_cells))
+	for i := range new_cells {
+		p, err := PackCell(new_cells[i])
+		if err != nil {
+			return err
+		}
+		packed[i] = p
+	}
+	for _, p := range packed {
+		pop[p] = true
+	}
+	return nil
+}
+
+func (pop PackedPopulation) AddCells(cells []Cell) error {
+	return pop.Add(cells)
+}
+
+func (pop PackedPopulation) Size() int {
+	return len(pop)
+}
+
+func (pop PackedPopulation) HasCell(cell Cell) bool {
+	packed, err := PackCell(cell)
+	return err == nil && pop[packed]
+}
+
+// Step calculates the next generation.  It fails with ErrCoordOverflow,
+// rather than wrapping around, if a live cell is on the edge of the int32
+// range where its neighbors can't be represented.
+func (current PackedPopulation) Step() (PackedPopulation, error) {
+	nextgen := make(PackedPopulation, len(current))
+	neighbor_count := make(map[PackedCell]int8, len(current)*4)
+	for cell := range current {
+		x, y := cell.xy()
+		if x == math.MinInt32 || x == math.MaxInt32 || y == math.MinInt32 || y == math.MaxInt32 {
+			return nil, ErrCoordOverflow
+		}
+		neighbor_count[packXY(x-1, y-1)]++
+		neighbor_count[packXY(x, y-1)]++
+		neighbor_count[packXY(x+1, y-1)]++
+		neighbor_count[packXY(x-1, y)]++
+		neighbor_count[packXY(x+1, y)]++
+		neighbor_count[packXY(x-1, y+1)]++
+		neighbor_count[packXY(x, y+1)]++
+		neighbor_count[packXY(x+1, y+1)]++
+	}
+
+	for cell, count := range neighbor_count {
+		if count == 3 || count == 2 && current[cell] {
+			nextgen[cell] = true
+		}
+	}
+
+	return nextgen, nil
+}
+
+func (current PackedPopulation) StepCells() (Cells, error) {
+	next, err := current.Step()
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+func (pop PackedPopulation) BoundingBox() (Cell, Cell) {
+	var min_x, min_y int32 = math.MaxInt32, math.MaxInt32
+	var max_x, max_y int32 = math.MinInt32, math.MinInt32
+	for cell := range pop {
+		x, y := cell.xy()
+		min_x, max_x = min(min_x, x), max(max_x, x)
+		min_y, max_y = min(min_y, y), max(max_y, y)
+	}
+	if len(pop) == 0 {
+		return Cell{math.MaxInt64, math.MaxInt64}, Cell{math.MinInt64, math.MinInt64}
+	}
+	return Cell{Coord(min_x), Coord(min_y)}, Cell{Coord(max_x), Coord(max_y)}
+}
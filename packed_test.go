@@ -0,0 +1,103 @@
+package golife_test
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestPackedStep(t *testing.T) {
+	game, err := golife.Load("test_files/turingmachine.rle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed, err := golife.PackPopulation(game.Population)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := packed.Step()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match, errmsg := samePop(game.Population.Step(), next.Population()); !match {
+		t.Errorf("Packed step disagrees with Step: %s", errmsg)
+	}
+
+	min_cell, max_cell := game.Population.BoundingBox()
+	packed_min, packed_max := packed.BoundingBox()
+	if min_cell != packed_min || max_cell != packed_max {
+		t.Errorf("Bounding boxes differ: %v-%v and %v-%v", min_cell, max_cell, packed_min, packed_max)
+	}
+}
+
+func TestPackedNegativeCoords(t *testing.T) {
+	packed := make(golife.PackedPopulation)
+	cells := golife.CellList{{-1, -5}, {0, -5}, {1, -5}}
+	if err := packed.Add(cells); err != nil {
+		t.Fatal(err)
+	}
+	for _, cell := range cells {
+		if !packed.HasCell(cell) {
+			t.Errorf("Cell %v lost in packing", cell)
+		}
+	}
+}
+
+func TestPackedOverflow(t *testing.T) {
+	packed := make(golife.PackedPopulation)
+	if err := packed.Add(golife.CellList{{0, 0}, {math.MaxInt32 + 1, 0}}); err != golife.ErrCoordOverflow {
+		t.Errorf("Expected overflow adding out of range cell, got %v", err)
+	}
+	if packed.Size() != 0 {
+		t.Error("Cells added despite overflow")
+	}
+
+	packed.Add(golife.CellList{{math.MaxInt32, 0}, {math.MaxInt32, 1}, {math.MaxInt32, 2}})
+	if _, err := packed.Step(); err != golife.ErrCoordOverflow {
+		t.Errorf("Expected overflow stepping at the edge of the range, got %v", err)
+	}
+}
+
+func TestCellsFollowGame(t *testing.T) {
+	gun := "x = 36, y = 9, rule = B3/S23\n24bo$22bobo$12b2o6b2o12b2o$11bo3bo4b2o12b2o$2o8bo5bo3b2o$2o8bo3bob2o4bobo$10bo5bo7bo$11bo3bo$12b2o!\n"
+	game, err := golife.Read(strings.NewReader(gun), "rle")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both kinds of population are driven only through the interface, in
+	// step with the game.
+	all := []golife.Cells{make(golife.Population), make(golife.PackedPopulation)}
+	for i := range all {
+		if err := all[i].AddCells(slices.Collect(game.Population.All())); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for gen := 1; gen <= 100; gen++ {
+		game.Next()
+		min_cell, max_cell := game.BoundingBox()
+		for i := range all {
+			all[i], err = all[i].StepCells()
+			if err != nil {
+				t.Fatalf("%T failed to step at generation %d: %v", all[i], gen, err)
+			}
+			if all[i].Size() != game.Population.Size() {
+				t.Fatalf("%T has %d cells at generation %d, the game %d", all[i], all[i].Size(), gen, game.Population.Size())
+			}
+			cells_min, cells_max := all[i].BoundingBox()
+			if cells_min != min_cell || cells_max != max_cell {
+				t.Fatalf("%T bounding box %v-%v at generation %d, the game %v-%v", all[i], cells_min, cells_max, gen, min_cell, max_cell)
+			}
+			for cell := range game.Population {
+				if !all[i].HasCell(cell) {
+					t.Fatalf("%T is missing %v at generation %d", all[i], cell, gen)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package golife
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// SeededSource is a random source that gives the same numbers for the same
+// seed string on every platform.  Its stream is the SHA-256 digests of the
+// seed followed by a big-endian 64 bit block counter starting at zero, each
+// digest read as four big-endian 64 bit words.  It satisfies
+// math/rand.Source64.
+type SeededSource struct {
+	seed    string
+	counter uint64
+	block   [sha256.Size]byte
+	used    int
+}
+
+func NewSeededSource(seed string) *SeededSource {
+	var source SeededSource
+	source.seed = seed
+	source.used = sha256.Size
+	return &source
+}
+
+func (source *SeededSource) Uint64() uint64 {
+	if source.used >= sha256.Size {
+		buf := make([]byte, len(source.seed)+8)
+		copy(buf, source.seed)
+		binary.BigEndian.PutUint64(buf[len(source.seed):], source.counter)
+		source.block = sha256.Sum256(buf)
+		source.counter += 1
+		source.used = 0
+	}
+	value := binary.BigEndian.Uint64(source.block[source.used:])
+	source.used += 8
+	return value
+}
+
+func (source *SeededSource) Int63() int64 {
+	return int64(source.Uint64() >> 1)
+}
+
+// Seed restarts the stream using the decimal form of seed as the string.
+func (source *SeededSource) Seed(seed int64) {
+	*source = *NewSeededSource(strconv.FormatInt(seed, 10))
+}
+
+// Symmetry names a symmetry the way Catagolue does: the group, then for
+// the rotations and orthogonal mirrors whether the center falls on a cell
+// (1), the middle of an edge (2) or a corner (4), and for D2 and D4 whether
+// the mirrors are orthogonal (+) or diagonal (x).
+type Symmetry string
+
+const (
+	C1       Symmetry = "C1"
+	C2_1     Symmetry = "C2_1"
+	C2_2     Symmetry = "C2_2"
+	C2_4     Symmetry = "C2_4"
+	C4_1     Symmetry = "C4_1"
+	C4_4     Symmetry = "C4_4"
+	D2_plus1 Symmetry = "D2_+1"
+	D2_plus2 Symmetry = "D2_+2"
+	D2_x     Symmetry = "D2_x"
+	D4_plus1 Symmetry = "D4_+1"
+	D4_plus2 Symmetry = "D4_+2"
+	D4_plus4 Symmetry = "D4_+4"
+	D4_x1    Symmetry = "D4_x1"
+	D4_x4    Symmetry = "D4_x4"
+	D8_1     Symmetry = "D8_1"
+	D8_4     Symmetry = "D8_4"
+)
+
+// centerSuffix returns the suffix for a rotation or pair of mirrors about the
+// middle of a rectangle of the given size.
+func centerSuffix(width, height Coord) string {
+	switch {
+	case width%2 == 1 && height%2 == 1:
+		return "1"
+	case width%2 == 0 && height%2 == 0:
+		return "4"
+	}
+	return "2"
+}
+
+// images returns the cells a cell is mapped to by the symmetry, in a
+// rectangle of the given size with its corner at the origin.  The D2_+
+// mirror is left to right, and the suffix has to match where the middle of
+// the rectangle falls.
+func (symmetry Symmetry) images(cell Cell, width, height Coord) ([]Cell, error) {
+	x, y := cell.X, cell.Y
+	rx, ry := width-1-x, height-1-y
+	var group string
+	var images []Cell
+	needs_square := false
+	switch symmetry {
+	case C1:
+		return []Cell{{x, y}}, nil
+	case C2_1, C2_2, C2_4:
+		group, images = "C2_"+centerSuffix(width, height), []Cell{{x, y}, {rx, ry}}
+	case C4_1, C4_4:
+		needs_square = true
+		group, images = "C4_"+centerSuffix(width, height), []Cell{{x, y}, {ry, x}, {rx, ry}, {y, rx}}
+	case D2_plus1, D2_plus2:
+		group, images = "D2_+"+centerSuffix(width, 1), []Cell{{x, y}, {rx, y}}
+	case D2_x:
+		needs_square = true
+		group, images = "D2_x", []Cell{{x, y}, {y, x}}
+	case D4_plus1, D4_plus2, D4_plus4:
+		group, images = "D4_+"+centerSuffix(width, height), []Cell{{x, y}, {rx, y}, {x, ry}, {rx, ry}}
+	case D4_x1, D4_x4:
+		needs_square = true
+		group, images = "D4_x"+centerSuffix(width, height), []Cell{{x, y}, {y, x}, {ry, rx}, {rx, ry}}
+	case D8_1, D8_4:
+		needs_square = true
+		group, images = "D8_"+centerSuffix(width, height), []Cell{{x, y}, {ry, x}, {rx, ry}, {y, rx}, {rx, y}, {x, ry}, {y, x}, {ry, rx}}
+	default:
+		return nil, errors.New("Unknown symmetry " + string(symmetry))
+	}
+
+	if needs_square && width != height {
+		return nil, errors.New("Symmetry " + string(symmetry) + " needs a square")
+	}
+	if Symmetry(group) != symmetry {
+		return nil, fmt.Errorf("A %dx%d rectangle has %s symmetry rather than %s", width, height, group, symmetry)
+	}
+	return images, nil
+}
+
+// soupBits is the bit stream soups are drawn from: the SHA-256 digest of
+// the seed, as apgsearch uses for its soups, and then if more bits are
+// needed the words of a SeededSource for the seed, most significant bit
+// first throughout.
+type soupBits struct {
+	digest [sha256.Size]byte
+	used   int
+	source *SeededSource
+	word   uint64
+	left   int
+}
+
+func newSoupBits(seed string) *soupBits {
+	var bits soupBits
+	bits.digest = sha256.Sum256([]byte(seed))
+	bits.source = NewSeededSource(seed)
+	return &bits
+}
+
+func (bits *soupBits) next() bool {
+	if bits.used < 8*sha256.Size {
+		bit := bits.digest[bits.used/8]&(0x80>>(bits.used%8)) != 0
+		bits.used += 1
+		return bit
+	}
+	if bits.left == 0 {
+		bits.word, bits.left = bits.source.Uint64(), 64
+	}
+	bits.left -= 1
+	return bits.word&(1<<bits.left) != 0
+}
+
+// alive decides a cell at the given density, strictly between 0 and 1, by
+// reading bits as the binary digits of a fraction, inverted, until it's
+// known whether the fraction is below the density.  It takes two bits on
+// average, and exactly one at a density of one half, where a set bit is a
+// live cell.
+func (bits *soupBits) alive(density float64) bool {
+	for {
+		density *= 2
+		digit := density >= 1
+		if digit {
+			density -= 1
+		}
+		if random := !bits.next(); random != digit {
+			return digit
+		}
+		if density == 0 {
+			return false
+		}
+	}
+}
+
+// FillSoup fills the rectangle from min_cell to max_cell with a random soup
+// of the given density and symmetry, replacing whatever was there.  Cells
+// are visited row by row from the top left, and each one that isn't the
+// image of a cell already visited is decided by soupBits for the seed (a
+// density of 1 or more fills every cell and 0 or less none, without drawing
+// any).  Its images under the symmetry are then set the same way, all as a single edit.  A 16x16
+// C1 soup at density one half is the apgsearch soup, as from SoupFromSeed.
+func (game *Game) FillSoup(min_cell, max_cell Cell, density float64, symmetry Symmetry, seed string) error {
+	width, height := max_cell.X-min_cell.X+1, max_cell.Y-min_cell.Y+1
+	if _, err := symmetry.images(Cell{0, 0}, width, height); err != nil {
+		return err
+	}
+	if !(density > 0) {
+		density = 0
+	}
+	bits := newSoupBits(seed)
+
+	soup := make(Population)
+	for y := Coord(0); y < height; y++ {
+		for x := Coord(0); x < width; x++ {
+			images, _ := symmetry.images(Cell{x, y}, width, height)
+			canonical := true
+			for _, image := range images {
+				if image.Y < y || image.Y == y && image.X < x {
+					canonical = false
+				}
+			}
+			if !canonical || density < 1 && (density == 0 || !bits.alive(density)) {
+				continue
+			}
+			for _, image := range images {
+				soup[Cell{min_cell.X + image.X, min_cell.Y + image.Y}] = true
+			}
+		}
+	}
+
+	deaths := make([]Cell, 0)
+	for _, cell := range game.CellsInRect(min_cell, max_cell) {
+		if !soup[cell] {
+			deaths = append(deaths, cell)
+		}
+	}
+	game.BeginEdit("soup")
+	game.changeCells(slices.Collect(soup.All()), deaths, true)
+	game.EndEdit()
+	return nil
+}
+
+// DetectSymmetry returns the largest symmetry group the population is
+// invariant under, about the center of its bounding box, named with the
+// suffix for where that center falls.  Unlike FillSoup, a D2_+ mirror may
+// be either way up.
+func DetectSymmetry(pop Population) Symmetry {
+	min_cell, max_cell := pop.BoundingBox()
+	width, height := max_cell.X-min_cell.X+1, max_cell.Y-min_cell.Y+1
+	if pop.Size() == 0 {
+		return C1
+	}
+	invariant := func(transform func(x, y Coord) (Coord, Coord)) bool {
+		for cell, present := range pop {
+			if !present {
+				continue
+			}
+			x, y := transform(cell.X-min_cell.X, cell.Y-min_cell.Y)
+			if !pop[Cell{min_cell.X + x, min_cell.Y + y}] {
+				return false
+			}
+		}
+		return true
+	}
+
+	square := width == height
+	rot90 := square && invariant(func(x, y Coord) (Coord, Coord) { return height - 1 - y, x })
+	rot180 := invariant(func(x, y Coord) (Coord, Coord) { return width - 1 - x, height - 1 - y })
+	mirrorX := invariant(func(x, y Coord) (Coord, Coord) { return width - 1 - x, y })
+	mirrorY := invariant(func(x, y Coord) (Coord, Coord) { return x, height - 1 - y })
+	diagonal := square && invariant(func(x, y Coord) (Coord, Coord) { return y, x })
+	antidiagonal := square && invariant(func(x, y Coord) (Coord, Coord) { return height - 1 - y, width - 1 - x })
+
+	middle := centerSuffix(width, height)
+	switch {
+	case rot90 && (mirrorX || diagonal):
+		return Symmetry("D8_" + middle)
+	case rot90:
+		return Symmetry("C4_" + middle)
+	case mirrorX && mirrorY:
+		return Symmetry("D4_+" + middle)
+	case diagonal && antidiagonal:
+		return Symmetry("D4_x" + middle)
+	case rot180:
+		return Symmetry("C2_" + middle)
+	case mirrorX:
+		return Symmetry("D2_+" + centerSuffix(width, 1))
+	case mirrorY:
+		return Symmetry("D2_+" + centerSuffix(height, 1))
+	case diagonal || antidiagonal:
+		return D2_x
+	}
+	return C1
+}
@@ -0,0 +1,175 @@
+package golife_test
+
+import (
+	"crypto/sha256"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestSeededSource(t *testing.T) {
+	first := rand.New(golife.NewSeededSource("abc"))
+	second := rand.New(golife.NewSeededSource("abc"))
+	other := golife.NewSeededSource("abd")
+	for range 20 {
+		if first.Uint64() != second.Uint64() {
+			t.Fatal("Same seed gave different streams")
+		}
+	}
+
+	// The first word is the start of SHA-256("abc" followed by eight zero bytes)
+	if word := golife.NewSeededSource("abc").Uint64(); word != 0x4b5c6fd314d0d83d {
+		t.Errorf("Unexpected first word %#x", word)
+	}
+	if golife.NewSeededSource("abc").Uint64() == other.Uint64() {
+		t.Error("Different seeds gave the same word")
+	}
+}
+
+func TestFillSoupSymmetry(t *testing.T) {
+	cases := []struct {
+		symmetry      golife.Symmetry
+		width, height golife.Coord
+	}{
+		{golife.C1, 16, 16},
+		{golife.C2_1, 15, 15},
+		{golife.C2_2, 16, 15},
+		{golife.C2_4, 16, 16},
+		{golife.C4_1, 15, 15},
+		{golife.C4_4, 16, 16},
+		{golife.D2_plus1, 15, 16},
+		{golife.D2_plus2, 16, 15},
+		{golife.D2_x, 16, 16},
+		{golife.D4_plus1, 15, 15},
+		{golife.D4_plus2, 15, 16},
+		{golife.D4_plus4, 16, 16},
+		{golife.D4_x1, 15, 15},
+		{golife.D4_x4, 16, 16},
+		{golife.D8_1, 15, 15},
+		{golife.D8_4, 16, 16},
+	}
+	for _, c := range cases {
+		game := golife.NewGame()
+		if err := game.FillSoup(golife.Cell{10, 20}, golife.Cell{9 + c.width, 19 + c.height}, 0.5, c.symmetry, "seed"); err != nil {
+			t.Fatal(err)
+		}
+		if game.Size() == 0 {
+			t.Errorf("%s soup is empty", c.symmetry)
+		}
+		rx, ry := c.width-1, c.height-1
+		for cell := range game.Population {
+			x, y := cell.X-10, cell.Y-20
+			mirrors := map[golife.Symmetry][]golife.Cell{
+				golife.C2_1:     {{rx - x, ry - y}},
+				golife.C2_2:     {{rx - x, ry - y}},
+				golife.C2_4:     {{rx - x, ry - y}},
+				golife.C4_1:     {{ry - y, x}},
+				golife.C4_4:     {{ry - y, x}},
+				golife.D2_plus1: {{rx - x, y}},
+				golife.D2_plus2: {{rx - x, y}},
+				golife.D2_x:     {{y, x}},
+				golife.D4_plus1: {{rx - x, y}, {x, ry - y}},
+				golife.D4_plus2: {{rx - x, y}, {x, ry - y}},
+				golife.D4_plus4: {{rx - x, y}, {x, ry - y}},
+				golife.D4_x1:    {{y, x}, {ry - y, rx - x}},
+				golife.D4_x4:    {{y, x}, {ry - y, rx - x}},
+				golife.D8_1:     {{rx - x, y}, {y, x}},
+				golife.D8_4:     {{rx - x, y}, {y, x}},
+			}
+			for _, image := range mirrors[c.symmetry] {
+				if !game.HasCell(golife.Cell{image.X + 10, image.Y + 20}) {
+					t.Errorf("%s soup missing image %v of %v", c.symmetry, image, cell)
+				}
+			}
+		}
+	}
+
+	again := golife.NewGame()
+	again.FillSoup(golife.Cell{0, 0}, golife.Cell{15, 15}, 0.5, golife.D4_plus4, "seed")
+	game := golife.NewGame()
+	game.FillSoup(golife.Cell{0, 0}, golife.Cell{15, 15}, 0.5, golife.D4_plus4, "seed")
+	if match, errmsg := samePop(game.Population, again.Population); !match {
+		t.Errorf("Same seed gave different soups: %s", errmsg)
+	}
+
+	if err := game.FillSoup(golife.Cell{0, 0}, golife.Cell{15, 7}, 0.5, golife.C4_4, "seed"); err == nil {
+		t.Error("C4 soup accepted in a rectangle that isn't square")
+	}
+	if err := game.FillSoup(golife.Cell{0, 0}, golife.Cell{15, 15}, 0.5, golife.C2_1, "seed"); err == nil {
+		t.Error("C2_1 soup accepted in a rectangle with its center on a corner")
+	}
+}
+
+// TestFillSoupMatchesApgsearch decodes the apgsearch soup straight from the
+// digest and checks FillSoup and SoupFromSeed both give it.
+func TestFillSoupMatchesApgsearch(t *testing.T) {
+	digest := sha256.Sum256([]byte("k_test0"))
+	expected := make(golife.Population)
+	for i, b := range digest {
+		for bit := range 8 {
+			if b&(0x80>>bit) != 0 {
+				expected[golife.Cell{X: golife.Coord(8*(i%2) + bit), Y: golife.Coord(i / 2)}] = true
+			}
+		}
+	}
+
+	game := golife.NewGame()
+	game.FillSoup(golife.Cell{0, 0}, golife.Cell{15, 15}, 0.5, golife.C1, "k_test0")
+	if match, errmsg := samePop(game.Population, expected); !match {
+		t.Errorf("FillSoup doesn't match the apgsearch soup: %s", errmsg)
+	}
+	if match, errmsg := samePop(golife.SoupFromSeed("k_test0"), expected); !match {
+		t.Errorf("SoupFromSeed doesn't match the apgsearch soup: %s", errmsg)
+	}
+}
+
+func TestFillSoupDensity(t *testing.T) {
+	cases := []struct {
+		density  float64
+		min, max int
+	}{
+		{-1, 0, 0},
+		{0, 0, 0},
+		{math.NaN(), 0, 0},
+		{0.25, 2000, 3000},
+		{math.Nextafter(1, 0), 10000, 10000},
+		{1, 10000, 10000},
+		{2, 10000, 10000},
+	}
+	for _, c := range cases {
+		game := golife.NewGame()
+		game.FillSoup(golife.Cell{0, 0}, golife.Cell{99, 99}, c.density, golife.C1, "seed")
+		if size := game.Size(); size < c.min || size > c.max {
+			t.Errorf("Expected %d to %d cells at density %v, got %d", c.min, c.max, c.density, size)
+		}
+	}
+}
+
+func TestDetectSymmetry(t *testing.T) {
+	cases := []struct {
+		name     string
+		cells    golife.CellList
+		expected golife.Symmetry
+	}{
+		{"glider", golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}}, golife.C1},
+		{"tee", golife.CellList{{0, 0}, {1, 0}, {2, 0}, {1, 1}}, golife.D2_plus1},
+		{"block", golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}}, golife.D8_4},
+		{"blinker", golife.CellList{{0, 0}, {1, 0}, {2, 0}}, golife.D4_plus1},
+		{"snake", golife.CellList{{0, 0}, {1, 0}, {3, 0}, {0, 1}, {2, 1}, {3, 1}}, golife.C2_4},
+		{"pinwheel", golife.CellList{{0, 0}, {1, 0}, {2, 0}, {2, 1}, {2, 2}, {1, 2}, {0, 2}, {0, 1}, {1, 1}}, golife.D8_1},
+		{"r-pentomino", golife.CellList{{1, 0}, {2, 0}, {0, 1}, {1, 1}, {1, 2}}, golife.C1},
+		{"c4 arms", golife.CellList{{1, 0}, {2, 0}, {0, 2}, {0, 1}, {3, 1}, {3, 2}, {1, 3}, {2, 3}}, golife.D8_4},
+		{"hook", golife.CellList{{0, 0}, {1, 0}, {0, 1}}, golife.D2_x},
+		{"diagonal pair", golife.CellList{{0, 0}, {1, 1}}, golife.D4_x4},
+		{"c4 swirl", golife.CellList{{0, 0}, {1, 0}, {3, 0}, {3, 1}, {3, 3}, {2, 3}, {0, 3}, {0, 2}}, golife.C4_4},
+	}
+	for _, c := range cases {
+		pop := make(golife.Population)
+		pop.Add(c.cells)
+		if symmetry := golife.DetectSymmetry(pop); symmetry != c.expected {
+			t.Errorf("Expected %s symmetry for %s, got %s", c.expected, c.name, symmetry)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package golife
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+)
+
+type RenderOptions struct {
+	CellSize  int
+	Grid      bool
+	Alive     color.Color
+	Dead      color.Color
+	GridColor color.Color
+
+	// HeatColors colors dead cells by their entry in Heat, hottest first:
+	// a heat of 1 uses HeatColors[0] and anything past the end uses the
+	// last one.  When exporting a GIF, Heat is filled in with how many
+	// generations ago each cell was last alive, leaving fading trails.
+	HeatColors []color.Color
+	Heat       map[Cell]int
+
+	// Delay between GIF frames in hundredths of a second.
+	Delay int
+}
+
+func DefaultRenderOptions() RenderOptions {
+	var opts RenderOptions
+	opts.CellSize = 4
+	opts.Alive = color.Black
+	opts.Dead = color.White
+	opts.GridColor = color.Gray{0xcc}
+	opts.Delay = 10
+	return opts
+}
+
+// withDefaults fills in anything left unset from DefaultRenderOptions.
+func (opts RenderOptions) withDefaults() RenderOptions {
+	defaults := DefaultRenderOptions()
+	if opts.CellSize < 1 {
+		opts.CellSize = defaults.CellSize
+	}
+	if opts.Alive == nil {
+		opts.Alive = defaults.Alive
+	}
+	if opts.Dead == nil {
+		opts.Dead = defaults.Dead
+	}
+	if opts.GridColor == nil {
+		opts.GridColor = defaults.GridColor
+	}
+	if len(opts.HeatColors) > 253 {
+		opts.HeatColors = opts.HeatColors[:253]
+	}
+	return opts
+}
+
+func (opts RenderOptions) palette() color.Palette {
+	palette := color.Palette{opts.Dead, opts.Alive, opts.GridColor}
+	return append(palette, opts.HeatColors...)
+}
+
+// Render draws the part of the population from min_cell to max_cell
+// inclusive, CellSize pixels to a cell.  Grid lines, when asked for, take
+// the top and left pixel of each cell, so cells need to be at least 3
+// pixels for them to show.  Options left unset take their default values.
+func Render(pop Population, min_cell, max_cell Cell, opts RenderOptions) *image.Paletted {
+	opts = opts.withDefaults()
+	width, height := 1, 1
+	if min_cell.X <= max_cell.X && min_cell.Y <= max_cell.Y {
+		width, height = int(max_cell.X-min_cell.X+1), int(max_cell.Y-min_cell.Y+1)
+	}
+	size := opts.CellSize
+	img := image.NewPaletted(image.Rect(0, 0, width*size, height*size), opts.palette())
+	grid := opts.Grid && size >= 3
+
+	for cy := 0; cy < height; cy++ {
+		for cx := 0; cx < width; cx++ {
+			cell := Cell{min_cell.X + Coord(cx), min_cell.Y + Coord(cy)}
+			var index uint8
+			if pop[cell] {
+				index = 1
+			} else if heat := opts.Heat[cell]; heat > 0 && len(opts.HeatColors) > 0 {
+				index = uint8(3 + min(heat, len(opts.HeatColors)) - 1)
+			}
+			for py := 0; py < size; py++ {
+				row := img.Pix[(cy*size+py)*img.Stride+cx*size:]
+				for px := 0; px < size; px++ {
+					if grid && (px == 0 || py == 0) {
+						row[px] = 2
+					} else {
+						row[px] = index
+					}
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+func (game *Game) WritePNG(w io.Writer, opts RenderOptions) error {
+	min_cell, max_cell := game.BoundingBox()
+	return png.Encode(w, Render(game.Population, min_cell, max_cell, opts))
+}
+
+func (game *Game) SavePNG(filepath string, opts RenderOptions) error {
+	fileWriter, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer fileWriter.Close()
+	return game.WritePNG(fileWriter, opts)
+}
+
+// WriteGIF writes an animation of generations from through to, moving the
+// game there with GoTo and then stepping it with Next.  Every frame shows
+// the area covered by the pattern over the whole animation, which means
+// running those generations twice.
+func (game *Game) WriteGIF(w io.Writer, from, to int, opts RenderOptions) error {
+	if to < from {
+		return errors.New("Animation ends before it starts")
+	}
+	if err := game.GoTo(from); err != nil {
+		return err
+	}
+
+	min_cell, max_cell := game.BoundingBox()
+	preview := game.Copy()
+	for preview.Generation < to {
+		preview.Next()
+		frame_min, frame_max := preview.BoundingBox()
+		min_cell.X, min_cell.Y = min(min_cell.X, frame_min.X), min(min_cell.Y, frame_min.Y)
+		max_cell.X, max_cell.Y = max(max_cell.X, frame_max.X), max(max_cell.Y, frame_max.Y)
+	}
+
+	opts = opts.withDefaults()
+	var anim gif.GIF
+	trail := len(opts.HeatColors) > 0
+	if trail {
+		opts.Heat = make(map[Cell]int)
+	}
+	for {
+		anim.Image = append(anim.Image, Render(game.Population, min_cell, max_cell, opts))
+		anim.Delay = append(anim.Delay, opts.Delay)
+		if game.Generation >= to {
+			break
+		}
+
+		if trail {
+			for cell, heat := range opts.Heat {
+				if heat >= len(opts.HeatColors) {
+					delete(opts.Heat, cell)
+				} else {
+					opts.Heat[cell] = heat + 1
+				}
+			}
+			for cell := range game.Population {
+				opts.Heat[cell] = 1
+			}
+		}
+		game.Next()
+	}
+
+	return gif.EncodeAll(w, &anim)
+}
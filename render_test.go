@@ -0,0 +1,72 @@
+package golife_test
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestRender(t *testing.T) {
+	opts := golife.DefaultRenderOptions()
+	opts.CellSize = 3
+	opts.Grid = true
+	img := golife.Render(popFromCells(testPattern), golife.Cell{0, -1}, golife.Cell{2, 1}, opts)
+
+	if bounds := img.Bounds(); bounds.Dx() != 9 || bounds.Dy() != 9 {
+		t.Fatalf("Expected a 9x9 image, got %v", bounds)
+	}
+	if img.At(4, 4) != opts.Alive || img.At(4, 1) != opts.Dead || img.At(3, 4) != opts.GridColor {
+		t.Errorf("Unexpected pixels %v %v %v", img.At(4, 4), img.At(4, 1), img.At(3, 4))
+	}
+}
+
+func TestSavePNG(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	path := filepath.Join(t.TempDir(), "blinker.png")
+	if err := game.SavePNG(path, golife.DefaultRenderOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	game.WritePNG(&buf, golife.RenderOptions{CellSize: 2})
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 6 || bounds.Dy() != 2 {
+		t.Errorf("Expected a 6x2 image, got %v", bounds)
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	opts := golife.DefaultRenderOptions()
+	opts.CellSize = 1
+	opts.HeatColors = []color.Color{color.Gray{0x80}, color.Gray{0xc0}}
+
+	var buf bytes.Buffer
+	if err := game.WriteGIF(&buf, 0, 8, opts); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 9 || game.Generation != 8 {
+		t.Errorf("Expected 9 frames ending at generation 8, got %d at %d", len(anim.Image), game.Generation)
+	}
+	if bounds := anim.Image[0].Bounds(); bounds.Dx() != 5 || bounds.Dy() != 5 {
+		t.Errorf("Frames should cover the glider's whole path, got %v", bounds)
+	}
+	r, g, b, _ := anim.Image[1].At(1, 0).RGBA()
+	if r != 0x8080 || g != 0x8080 || b != 0x8080 {
+		t.Errorf("Expected a trail where the glider was, got %v", anim.Image[1].At(1, 0))
+	}
+}
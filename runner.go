@@ -0,0 +1,166 @@
+package golife
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type RunEvent struct {
+	Generation int
+	Population int
+	Min, Max   Cell
+	Status     Status
+}
+
+// Runner steps a SafeGame in the background.  Readers follow along with the
+// game's snapshots, or with the events it publishes after each generation.
+type Runner struct {
+	game    *SafeGame
+	events  chan RunEvent
+	wake    chan struct{}
+	dropped atomic.Uint64
+
+	mutex   sync.Mutex
+	speed   float64
+	paused  bool
+	pending int
+
+	// The clock the runner keeps its pace by, which tests replace.
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// NewRunner makes a runner for game that starts out paused.  Events are
+// buffered up to buffer deep, beyond that they are dropped rather than
+// holding up the game.
+func NewRunner(game *SafeGame, buffer int) *Runner {
+	var runner Runner
+	runner.game = game
+	runner.events = make(chan RunEvent, buffer)
+	runner.wake = make(chan struct{}, 1)
+	runner.paused = true
+	runner.now = time.Now
+	runner.after = time.After
+	return &runner
+}
+
+func (runner *Runner) Events() <-chan RunEvent {
+	return runner.events
+}
+
+// Dropped returns the number of events that were dropped because the
+// consumer wasn't keeping up.
+func (runner *Runner) Dropped() uint64 {
+	return runner.dropped.Load()
+}
+
+// SetSpeed sets the target generations per second, 0 runs as fast as
+// possible.
+func (runner *Runner) SetSpeed(generationsPerSecond float64) {
+	runner.update(func() {
+		runner.speed = generationsPerSecond
+	})
+}
+
+func (runner *Runner) Pause() {
+	runner.update(func() {
+		runner.paused = true
+	})
+}
+
+func (runner *Runner) Resume() {
+	runner.update(func() {
+		runner.paused = false
+	})
+}
+
+func (runner *Runner) Paused() bool {
+	runner.mutex.Lock()
+	defer runner.mutex.Unlock()
+	return runner.paused
+}
+
+// Step runs a single generation while paused.
+func (runner *Runner) Step() {
+	runner.update(func() {
+		runner.pending += 1
+	})
+}
+
+func (runner *Runner) update(f func()) {
+	runner.mutex.Lock()
+	f()
+	runner.mutex.Unlock()
+	select {
+	case runner.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run steps the game until ctx is cancelled, and then returns ctx.Err().
+// The game is paused automatically if it dies out.
+func (runner *Runner) Run(ctx context.Context) error {
+	var next time.Time
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		runner.mutex.Lock()
+		paused, speed := runner.paused, runner.speed
+		single := paused && runner.pending > 0
+		if single {
+			runner.pending -= 1
+		}
+		runner.mutex.Unlock()
+
+		if single {
+			runner.step()
+			continue
+		}
+
+		var wait <-chan time.Time
+		if !paused {
+			now := runner.now()
+			if speed <= 0 || !now.Before(next) {
+				runner.step()
+				if speed > 0 {
+					interval := time.Duration(float64(time.Second) / speed)
+					next = next.Add(interval)
+					if next.Before(now) {
+						next = now.Add(interval)
+					}
+				}
+				continue
+			}
+			wait = runner.after(next.Sub(now))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-runner.wake:
+		case <-wait:
+		}
+	}
+}
+
+func (runner *Runner) step() {
+	status := runner.game.Next()
+	if status == Extinct {
+		runner.Pause()
+	}
+
+	snapshot := runner.game.Snapshot()
+	var event RunEvent
+	event.Generation = snapshot.Generation
+	event.Population = snapshot.Population.Size()
+	event.Min, event.Max = snapshot.Population.BoundingBox()
+	event.Status = snapshot.Status
+	select {
+	case runner.events <- event:
+	default:
+		runner.dropped.Add(1)
+	}
+}
@@ -0,0 +1,167 @@
+package golife_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func startRunner(t *testing.T, game *golife.Game) (*golife.SafeGame, *golife.Runner, context.CancelFunc, chan error) {
+	safe := golife.NewSafeGame(game)
+	runner := golife.NewRunner(safe, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+	t.Cleanup(cancel)
+	return safe, runner, cancel, done
+}
+
+// hookAt calls f from inside Next as the game reaches the generation, so
+// the runner can be stopped at an exact point rather than after some time.
+func hookAt(game *golife.Game, generation int, f func()) {
+	game.AddHook(func(game *golife.Game, event golife.GameEvent) {
+		if event.Kind == golife.NextEvent && game.Generation == generation {
+			f()
+		}
+	})
+}
+
+func TestRunnerStepAndEvents(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	safe, runner, cancel, done := startRunner(t, game)
+
+	runner.Step()
+	runner.Step()
+	for gen := 1; gen <= 2; gen++ {
+		event := <-runner.Events()
+		if event.Generation != gen || event.Population != 3 {
+			t.Errorf("Unexpected event %+v", event)
+		}
+	}
+	if !runner.Paused() || safe.Snapshot().Generation != 2 {
+		t.Errorf("Single steps should leave the runner paused at generation 2")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRunnerPause(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	var runner *golife.Runner
+	hookAt(game, 5, func() {
+		runner.Pause()
+	})
+	safe, runner, cancel, done := startRunner(t, game)
+
+	runner.Resume()
+	for gen := 1; gen <= 5; gen++ {
+		if event := <-runner.Events(); event.Generation != gen {
+			t.Fatalf("Expected generation %d, got %d", gen, event.Generation)
+		}
+	}
+
+	// Had it kept running after the pause, the step would be further on.
+	runner.Step()
+	if event := <-runner.Events(); event.Generation != 6 || !runner.Paused() {
+		t.Errorf("Expected a single step to generation 6 while paused, got %d", event.Generation)
+	}
+	if generation := safe.Snapshot().Generation; generation != 6 {
+		t.Errorf("Expected to be at generation 6, at %d", generation)
+	}
+
+	cancel()
+	<-done
+}
+
+// TestRunnerSpeed runs the runner on a clock that jumps straight to
+// whatever time it waits for, and checks the generations are stepped at
+// exactly the right times on that clock.
+func TestRunnerSpeed(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	times := make([]time.Duration, 0)
+	game.AddHook(func(game *golife.Game, event golife.GameEvent) {
+		times = append(times, now.Sub(start))
+	})
+	var runner *golife.Runner
+	hookAt(game, 10, func() {
+		runner.Pause()
+	})
+
+	safe := golife.NewSafeGame(game)
+	runner = golife.NewRunner(safe, 100)
+	golife.SetRunnerClock(runner, func() time.Time {
+		return now
+	}, func(wait time.Duration) <-chan time.Time {
+		now = now.Add(wait)
+		ready := make(chan time.Time, 1)
+		ready <- now
+		return ready
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	runner.SetSpeed(20)
+	runner.Resume()
+	for range 10 {
+		<-runner.Events()
+	}
+	cancel()
+	<-done
+
+	for i, at := range times {
+		if at != time.Duration(i)*50*time.Millisecond {
+			t.Errorf("Expected generation %d at %v at 20 per second, got %v", i+1, time.Duration(i)*50*time.Millisecond, at)
+		}
+	}
+	if len(times) != 10 {
+		t.Errorf("Expected 10 generations before pausing, got %d", len(times))
+	}
+}
+
+func TestRunnerDropsEvents(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hookAt(game, 3, cancel)
+	safe := golife.NewSafeGame(game)
+	runner := golife.NewRunner(safe, 1)
+
+	runner.Resume()
+	runner.Run(ctx)
+	if runner.Dropped() != 2 || len(runner.Events()) != 1 {
+		t.Errorf("Expected the events for generations 2 and 3 to be dropped, dropped %d", runner.Dropped())
+	}
+}
+
+func TestRunnerPausesOnExtinction(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCell(golife.Cell{0, 0})
+	_, runner, _, _ := startRunner(t, game)
+
+	runner.Resume()
+	event := <-runner.Events()
+	if event.Status != golife.Extinct {
+		t.Errorf("Expected extinction, got %v", event.Status)
+	}
+	// The runner pauses before it publishes the event.
+	if !runner.Paused() {
+		t.Error("Runner kept going after extinction")
+	}
+}
@@ -0,0 +1,122 @@
+package golife
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Snapshot is an immutable view of one generation of a SafeGame.  Its
+// Population is shared with the game and must not be modified.
+type Snapshot struct {
+	Generation int
+	Population Population
+	Status     Status
+}
+
+// SafeGame wraps a Game so that it can be stepped and edited from one
+// goroutine while others read it.  Every change publishes a new Snapshot,
+// and populations are copied before being edited in place, so a Snapshot
+// never changes once it has been handed out.
+type SafeGame struct {
+	mutex    sync.RWMutex
+	game     *Game
+	snapshot atomic.Pointer[Snapshot]
+}
+
+// NewSafeGame takes ownership of game, which shouldn't be used directly
+// afterwards.
+func NewSafeGame(game *Game) *SafeGame {
+	var safe SafeGame
+	safe.game = game
+	safe.publish()
+	return &safe
+}
+
+func (safe *SafeGame) publish() {
+	status, _ := safe.game.Status()
+	safe.snapshot.Store(&Snapshot{safe.game.Generation, safe.game.Population, status})
+}
+
+// Snapshot returns the most recently published generation without taking
+// any locks or copying anything.
+func (safe *SafeGame) Snapshot() *Snapshot {
+	return safe.snapshot.Load()
+}
+
+// Read calls f with the game held under a read lock, for looking at things
+// a Snapshot doesn't carry, such as Name or Comments.  f must not modify
+// the game.
+func (safe *SafeGame) Read(f func(game *Game)) {
+	safe.mutex.RLock()
+	defer safe.mutex.RUnlock()
+	f(safe.game)
+}
+
+// Edit calls f with the game held under the write lock, after giving it a
+// private copy of the population to change.  Batching edits into one call
+// means the population is copied only once.
+func (safe *SafeGame) Edit(f func(game *Game)) {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	safe.game.Population = safe.game.Population.copy()
+	f(safe.game)
+	safe.publish()
+}
+
+func (safe *SafeGame) Next() Status {
+	safe.mutex.Lock()
+	defer safe.mutex.Unlock()
+	status := safe.game.Next()
+	safe.publish()
+	return status
+}
+
+func (safe *SafeGame) Previous() error {
+	var err error
+	safe.Edit(func(game *Game) {
+		err = game.Previous()
+	})
+	return err
+}
+
+func (safe *SafeGame) GoTo(generation int) error {
+	var err error
+	safe.Edit(func(game *Game) {
+		err = game.GoTo(generation)
+	})
+	return err
+}
+
+func (safe *SafeGame) AddCell(cell Cell) {
+	safe.Edit(func(game *Game) {
+		game.AddCell(cell)
+	})
+}
+
+func (safe *SafeGame) AddCells(cells []Cell) {
+	safe.Edit(func(game *Game) {
+		game.AddCells(cells)
+	})
+}
+
+func (safe *SafeGame) RemoveCell(cell Cell) {
+	safe.Edit(func(game *Game) {
+		game.RemoveCell(cell)
+	})
+}
+
+func (safe *SafeGame) Undo() error {
+	var err error
+	safe.Edit(func(game *Game) {
+		err = game.Undo()
+	})
+	return err
+}
+
+func (safe *SafeGame) Redo() error {
+	var err error
+	safe.Edit(func(game *Game) {
+		err = game.Redo()
+	})
+	return err
+}
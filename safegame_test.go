@@ -0,0 +1,69 @@
+package golife_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestSnapshotIsolation(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	safe := golife.NewSafeGame(game)
+
+	before := safe.Snapshot()
+	safe.AddCell(golife.Cell{10, 10})
+	safe.Next()
+	if before.Generation != 0 || before.Population.Size() != 3 {
+		t.Errorf("Snapshot changed after edits: %v at %d", before.Population, before.Generation)
+	}
+	if after := safe.Snapshot(); after.Generation != 1 || after.Population.Size() != 3 {
+		t.Errorf("Unexpected snapshot after step: %v at %d", after.Population, after.Generation)
+	}
+}
+
+func TestConcurrentReaders(t *testing.T) {
+	game := golife.NewGame()
+	game.Population = golife.SoupFromSeed("k_test0")
+	game.SetHistorySize(-1)
+	game.SetKeyframeInterval(10)
+	safe := golife.NewSafeGame(game)
+
+	var wg sync.WaitGroup
+	done := make(chan bool)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				snapshot := safe.Snapshot()
+				count := 0
+				for range snapshot.Population {
+					count += 1
+				}
+				if count != snapshot.Population.Size() {
+					t.Error("Snapshot changed while being read")
+				}
+			}
+		}()
+	}
+
+	for range 100 {
+		safe.Next()
+	}
+	for range 50 {
+		safe.Previous()
+	}
+	close(done)
+	wg.Wait()
+
+	if generation := safe.Snapshot().Generation; generation != 50 {
+		t.Errorf("Expected generation 50, got %d", generation)
+	}
+}
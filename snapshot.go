@@ -0,0 +1,499 @@
+package golife
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strconv"
+)
+
+const snapshot_version = 1
+
+var snapshot_magic = []byte("GLSN")
+
+var ErrBadSnapshot = errors.New("Corrupt or truncated snapshot")
+
+// Sections of a binary snapshot.  Each one is written as its kind, the
+// length of its payload as a uvarint, the payload and a CRC-32 of the
+// payload, so damage is caught section by section.
+const (
+	section_end byte = iota
+	section_meta
+	section_population
+	section_history
+	section_delta_settings
+	section_delta
+	section_keyframe
+)
+
+// jsonCells writes a list of cells as [x, y] pairs.
+type jsonCells []Cell
+
+func (cells jsonCells) MarshalJSON() ([]byte, error) {
+	pairs := make([][2]Coord, len(cells))
+	for i, cell := range cells {
+		pairs[i] = [2]Coord{cell.X, cell.Y}
+	}
+	return json.Marshal(pairs)
+}
+
+func (cells *jsonCells) UnmarshalJSON(data []byte) error {
+	var pairs [][2]Coord
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return err
+	}
+	*cells = make(jsonCells, len(pairs))
+	for i, pair := range pairs {
+		(*cells)[i] = Cell{pair[0], pair[1]}
+	}
+	return nil
+}
+
+// MarshalJSON writes the live cells as a list of [x, y] pairs, sorted the
+// same way as All.
+func (pop Population) MarshalJSON() ([]byte, error) {
+	return jsonCells(slices.Collect(pop.All())).MarshalJSON()
+}
+
+func (pop *Population) UnmarshalJSON(data []byte) error {
+	var cells jsonCells
+	if err := cells.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*pop = make(Population, len(cells))
+	pop.Add(CellList(cells))
+	return nil
+}
+
+type deltaJSON struct {
+	Births jsonCells `json:"births,omitempty"`
+	Deaths jsonCells `json:"deaths,omitempty"`
+}
+
+type deltaHistoryJSON struct {
+	Interval  int                `json:"interval"`
+	Base      int                `json:"base"`
+	Deltas    []deltaJSON        `json:"deltas"`
+	Keyframes map[int]Population `json:"keyframes"`
+}
+
+type cycleJSON struct {
+	MaxPeriod int      `json:"maxPeriod"`
+	Status    Status   `json:"status"`
+	Period    int      `json:"period"`
+	Hashes    []uint64 `json:"hashes,omitempty"`
+}
+
+type gameJSON struct {
+	Version     int               `json:"version"`
+	Filename    string            `json:"filename,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	Comments    []string          `json:"comments,omitempty"`
+	Generation  int               `json:"generation"`
+	HistorySize int               `json:"historySize"`
+	Population  Population        `json:"population"`
+	History     []Population      `json:"history,omitempty"`
+	Cycles      *cycleJSON        `json:"cycleDetection,omitempty"`
+	Deltas      *deltaHistoryJSON `json:"deltaHistory,omitempty"`
+}
+
+// MarshalJSON writes everything needed to carry on exactly where the game
+// left off: the population, generation, metadata, history (either kind)
+// and cycle detection state.  The timeline, journal, hooks and spatial
+// index aren't included.
+func (game *Game) MarshalJSON() ([]byte, error) {
+	game.saveEditedDelta()
+	state := gameJSON{
+		Version:     snapshot_version,
+		Filename:    game.Filename,
+		Name:        game.Name,
+		Author:      game.Author,
+		Comments:    game.Comments,
+		Generation:  game.Generation,
+		HistorySize: game.HistorySize,
+		Population:  game.Population,
+		History:     game.History,
+	}
+	if game.cycleLimit > 0 || game.status != Running {
+		state.Cycles = &cycleJSON{game.cycleLimit, game.status, game.period, game.hashes}
+	}
+	if history := game.deltas; history != nil {
+		state.Deltas = &deltaHistoryJSON{history.interval, history.base, make([]deltaJSON, len(history.deltas)), history.keyframes}
+		for i, delta := range history.deltas {
+			state.Deltas.Deltas[i] = deltaJSON{delta.births, delta.deaths}
+		}
+	}
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON replaces the state of the game with one written by
+// MarshalJSON.  Hooks stay in place; the timeline and journal are dropped.
+func (game *Game) UnmarshalJSON(data []byte) error {
+	var state gameJSON
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.Version != snapshot_version {
+		return fmt.Errorf("Unsupported snapshot version %d", state.Version)
+	}
+	if state.Population == nil {
+		state.Population = make(Population)
+	}
+	if state.Comments == nil {
+		state.Comments = make([]string, 0, 10)
+	}
+
+	var history *deltaHistory
+	if state.Deltas != nil {
+		if state.Deltas.Interval <= 0 {
+			return errors.New("Snapshot has a bad keyframe interval")
+		}
+		history = newDeltaHistory(state.Deltas.Interval, state.Population, state.Generation)
+		history.base = state.Deltas.Base
+		for _, delta := range state.Deltas.Deltas {
+			history.deltas = append(history.deltas, generationDelta{delta.Births, delta.Deaths})
+		}
+		clear(history.keyframes)
+		for generation, pop := range state.Deltas.Keyframes {
+			history.keyframes[generation] = pop
+		}
+	}
+
+	game.restore(state.Filename, state.Name, state.Author, state.Comments, state.Generation, state.HistorySize, state.Population, state.History, history)
+	if state.Cycles != nil {
+		game.cycleLimit = state.Cycles.MaxPeriod
+		game.status = state.Cycles.Status
+		game.period = state.Cycles.Period
+		game.hashes = state.Cycles.Hashes
+	}
+	return nil
+}
+
+func (game *Game) restore(filename, name, author string, comments []string, generation, historySize int, pop Population, popHistory []Population, history *deltaHistory) {
+	game.Filename = filename
+	game.Name = name
+	game.Author = author
+	game.Comments = comments
+	game.Generation = generation
+	game.HistorySize = historySize
+	game.Population = pop
+	game.History = popHistory
+	game.deltas = history
+	game.cycleLimit = 0
+	game.resetStatus()
+	game.timeline = nil
+	game.journal = nil
+	game.invalidateIndex()
+}
+
+// ReadJSON reads a game written with MarshalJSON.
+func ReadJSON(reader io.Reader) (*Game, error) {
+	game := NewGame()
+	if err := json.NewDecoder(reader).Decode(game); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+func (game *Game) WriteJSON(writer io.Writer) error {
+	return json.NewEncoder(writer).Encode(game)
+}
+
+func appendString(buf []byte, text string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(text)))
+	return append(buf, text...)
+}
+
+// appendCells writes the number of cells followed by each cell's offset
+// from the one before, which keeps sorted populations small.
+func appendCells(buf []byte, cells []Cell) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(cells)))
+	var prev Cell
+	for _, cell := range cells {
+		buf = binary.AppendVarint(buf, int64(cell.Y-prev.Y))
+		buf = binary.AppendVarint(buf, int64(cell.X-prev.X))
+		prev = cell
+	}
+	return buf
+}
+
+func appendPopulation(buf []byte, pop Population) []byte {
+	return appendCells(buf, slices.Collect(pop.All()))
+}
+
+func writeSection(writer io.Writer, kind byte, payload []byte) error {
+	section := []byte{kind}
+	section = binary.AppendUvarint(section, uint64(len(payload)))
+	section = append(section, payload...)
+	section = binary.BigEndian.AppendUint32(section, crc32.ChecksumIEEE(payload))
+	_, err := writer.Write(section)
+	return err
+}
+
+// WriteSnapshot writes the same state as MarshalJSON in a compact binary
+// form, starting with a magic number and version, and with a checksum on
+// every section.
+func (game *Game) WriteSnapshot(writer io.Writer) error {
+	game.saveEditedDelta()
+	if _, err := writer.Write(append(snapshot_magic, snapshot_version)); err != nil {
+		return err
+	}
+
+	meta := appendString(nil, game.Filename)
+	meta = appendString(meta, game.Name)
+	meta = appendString(meta, game.Author)
+	meta = binary.AppendUvarint(meta, uint64(len(game.Comments)))
+	for _, comment := range game.Comments {
+		meta = appendString(meta, comment)
+	}
+	meta = binary.AppendVarint(meta, int64(game.Generation))
+	meta = binary.AppendVarint(meta, int64(game.HistorySize))
+	meta = binary.AppendVarint(meta, int64(game.cycleLimit))
+	meta = binary.AppendUvarint(meta, uint64(game.status))
+	meta = binary.AppendUvarint(meta, uint64(game.period))
+	meta = binary.AppendUvarint(meta, uint64(len(game.hashes)))
+	for _, hash := range game.hashes {
+		meta = binary.BigEndian.AppendUint64(meta, hash)
+	}
+	if err := writeSection(writer, section_meta, meta); err != nil {
+		return err
+	}
+
+	if err := writeSection(writer, section_population, appendPopulation(nil, game.Population)); err != nil {
+		return err
+	}
+	for _, pop := range game.History {
+		if err := writeSection(writer, section_history, appendPopulation(nil, pop)); err != nil {
+			return err
+		}
+	}
+
+	if history := game.deltas; history != nil {
+		settings := binary.AppendVarint(nil, int64(history.interval))
+		settings = binary.AppendVarint(settings, int64(history.base))
+		if err := writeSection(writer, section_delta_settings, settings); err != nil {
+			return err
+		}
+		for _, delta := range history.deltas {
+			payload := appendCells(nil, delta.births)
+			payload = appendCells(payload, delta.deaths)
+			if err := writeSection(writer, section_delta, payload); err != nil {
+				return err
+			}
+		}
+		generations := make([]int, 0, len(history.keyframes))
+		for generation := range history.keyframes {
+			generations = append(generations, generation)
+		}
+		slices.Sort(generations)
+		for _, generation := range generations {
+			payload := binary.AppendVarint(nil, int64(generation))
+			payload = appendPopulation(payload, history.keyframes[generation])
+			if err := writeSection(writer, section_keyframe, payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	return writeSection(writer, section_end, nil)
+}
+
+// snapshotDecoder reads values from a section payload, remembering the
+// first thing that went wrong so the callers can check once at the end.
+type snapshotDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *snapshotDecoder) uvarint() uint64 {
+	value, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = ErrBadSnapshot
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+func (d *snapshotDecoder) varint() int64 {
+	value, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = ErrBadSnapshot
+		return 0
+	}
+	d.data = d.data[n:]
+	return value
+}
+
+// count reads a number of items that each take at least size bytes, which
+// can't be more than are left in the payload.
+func (d *snapshotDecoder) count(size int) int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)/size) {
+		d.err = ErrBadSnapshot
+		return 0
+	}
+	return int(n)
+}
+
+func (d *snapshotDecoder) string() string {
+	n := d.count(1)
+	text := string(d.data[:n])
+	d.data = d.data[n:]
+	return text
+}
+
+func (d *snapshotDecoder) uint64() uint64 {
+	if len(d.data) < 8 {
+		d.err = ErrBadSnapshot
+		return 0
+	}
+	value := binary.BigEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return value
+}
+
+func (d *snapshotDecoder) cells() []Cell {
+	cells := make([]Cell, d.count(2))
+	var prev Cell
+	for i := range cells {
+		prev.Y += Coord(d.varint())
+		prev.X += Coord(d.varint())
+		cells[i] = prev
+	}
+	return cells
+}
+
+func (d *snapshotDecoder) population() Population {
+	cells := d.cells()
+	pop := make(Population, len(cells))
+	pop.Add(cells)
+	return pop
+}
+
+func readSection(reader *bytes.Reader) (byte, *snapshotDecoder, error) {
+	kind, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, ErrBadSnapshot
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil || length > uint64(reader.Len()) {
+		return 0, nil, ErrBadSnapshot
+	}
+	payload := make([]byte, length)
+	reader.Read(payload)
+	var checksum [4]byte
+	if n, _ := reader.Read(checksum[:]); n != 4 {
+		return 0, nil, ErrBadSnapshot
+	}
+	if binary.BigEndian.Uint32(checksum[:]) != crc32.ChecksumIEEE(payload) {
+		return 0, nil, fmt.Errorf("Snapshot checksum mismatch in section %d", kind)
+	}
+	return kind, &snapshotDecoder{data: payload}, nil
+}
+
+// ReadSnapshot reads a game written with WriteSnapshot, checking every
+// section's checksum along the way.
+func ReadSnapshot(reader io.Reader) (*Game, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, snapshot_magic) || len(data) < len(snapshot_magic)+1 {
+		return nil, errors.New("Not a golife snapshot")
+	}
+	if version := data[len(snapshot_magic)]; version != snapshot_version {
+		return nil, errors.New("Unsupported snapshot version " + strconv.Itoa(int(version)))
+	}
+	sections := bytes.NewReader(data[len(snapshot_magic)+1:])
+
+	game := NewGame()
+	var cycleLimit, period int
+	var status Status
+	var hashes []uint64
+	var pop Population
+	popHistory := make([]Population, 0)
+	var history *deltaHistory
+
+	kind, d, err := readSection(sections)
+	if err != nil {
+		return nil, err
+	}
+	if kind != section_meta {
+		return nil, ErrBadSnapshot
+	}
+	game.Filename = d.string()
+	game.Name = d.string()
+	game.Author = d.string()
+	for n := d.count(1); n > 0; n-- {
+		game.Comments = append(game.Comments, d.string())
+	}
+	game.Generation = int(d.varint())
+	game.HistorySize = int(d.varint())
+	cycleLimit = int(d.varint())
+	status = Status(d.uvarint())
+	period = int(d.uvarint())
+	for n := d.count(8); n > 0; n-- {
+		hashes = append(hashes, d.uint64())
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	for kind != section_end {
+		kind, d, err = readSection(sections)
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case section_population:
+			pop = d.population()
+		case section_history:
+			popHistory = append(popHistory, d.population())
+		case section_delta_settings:
+			interval := int(d.varint())
+			if interval <= 0 {
+				return nil, ErrBadSnapshot
+			}
+			history = newDeltaHistory(interval, make(Population), game.Generation)
+			history.base = int(d.varint())
+			clear(history.keyframes)
+		case section_delta:
+			if history == nil {
+				return nil, ErrBadSnapshot
+			}
+			history.deltas = append(history.deltas, generationDelta{d.cells(), d.cells()})
+		case section_keyframe:
+			if history == nil {
+				return nil, ErrBadSnapshot
+			}
+			generation := int(d.varint())
+			history.keyframes[generation] = d.population()
+		case section_end:
+		default:
+			return nil, fmt.Errorf("Unknown snapshot section %d", kind)
+		}
+		if d.err != nil {
+			return nil, d.err
+		}
+	}
+	if pop == nil {
+		return nil, ErrBadSnapshot
+	}
+	if len(popHistory) == 0 && game.HistorySize == 0 {
+		popHistory = nil
+	}
+
+	game.restore(game.Filename, game.Name, game.Author, game.Comments, game.Generation, game.HistorySize, pop, popHistory, history)
+	game.cycleLimit = cycleLimit
+	game.status = status
+	game.period = period
+	game.hashes = hashes
+	return game, nil
+}
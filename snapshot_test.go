@@ -0,0 +1,185 @@
+package golife_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+// checkResumed runs both games back through their history and then on a
+// few generations, expecting them to stay in step.
+func checkResumed(t *testing.T, original, resumed *golife.Game) {
+	if resumed.Generation != original.Generation || resumed.HistorySize != original.HistorySize {
+		t.Fatalf("Expected generation %d and history size %d, got %d and %d",
+			original.Generation, original.HistorySize, resumed.Generation, resumed.HistorySize)
+	}
+	if resumed.Name != original.Name || len(resumed.Comments) != len(original.Comments) {
+		t.Errorf("Metadata not restored: %q %v", resumed.Name, resumed.Comments)
+	}
+	if status, _ := resumed.Status(); status != golife.Running {
+		t.Errorf("Expected status running, got %s", status)
+	}
+
+	back := original
+	for range 5 {
+		if err := resumed.Previous(); err != nil {
+			t.Fatal("History not restored: ", err)
+		}
+		back.Previous()
+		if match, errmsg := samePop(back.Population, resumed.Population); !match {
+			t.Fatalf("Generation %d differs going back: %s", resumed.Generation, errmsg)
+		}
+	}
+	for range 10 {
+		resumed.Next()
+		back.Next()
+	}
+	if match, errmsg := samePop(back.Population, resumed.Population); !match {
+		t.Errorf("Generation %d differs going forward: %s", resumed.Generation, errmsg)
+	}
+}
+
+func snapshotGame(keyframeInterval int) *golife.Game {
+	game := golife.NewGame()
+	game.Name = "k_test0"
+	game.Comments = append(game.Comments, "a soup")
+	game.Population = golife.SoupFromSeed("k_test0")
+	game.SetHistorySize(20)
+	game.SetKeyframeInterval(keyframeInterval)
+	game.SetCycleDetection(8)
+	for range 30 {
+		game.Next()
+	}
+	return game
+}
+
+func TestGameJSON(t *testing.T) {
+	for _, interval := range []int{0, 4} {
+		game := snapshotGame(interval)
+		data, err := json.Marshal(game)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resumed := golife.NewGame()
+		if err := json.Unmarshal(data, resumed); err != nil {
+			t.Fatal(err)
+		}
+		checkResumed(t, game, resumed)
+	}
+
+	if err := json.Unmarshal([]byte(`{"version":99}`), golife.NewGame()); err == nil {
+		t.Error("Accepted an unknown version")
+	}
+}
+
+func TestBinarySnapshot(t *testing.T) {
+	for _, interval := range []int{0, 4} {
+		game := snapshotGame(interval)
+		var buf bytes.Buffer
+		if err := game.WriteSnapshot(&buf); err != nil {
+			t.Fatal(err)
+		}
+		resumed, err := golife.ReadSnapshot(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkResumed(t, game, resumed)
+	}
+}
+
+func TestCorruptSnapshot(t *testing.T) {
+	var buf bytes.Buffer
+	if err := snapshotGame(0).WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	for _, at := range []int{10, len(data) / 2, len(data) - 6} {
+		corrupt := bytes.Clone(data)
+		corrupt[at] ^= 0x10
+		if _, err := golife.ReadSnapshot(bytes.NewReader(corrupt)); err == nil {
+			t.Errorf("Didn't notice byte %d of %d was changed", at, len(data))
+		}
+	}
+	if _, err := golife.ReadSnapshot(bytes.NewReader(data[:len(data)-3])); err == nil {
+		t.Error("Didn't notice the snapshot was truncated")
+	}
+}
+
+// TestSnapshotLayout decodes a snapshot by hand, following the documented
+// layout, rather than trusting ReadSnapshot to check its own output.
+func TestSnapshotLayout(t *testing.T) {
+	game := snapshotGame(0)
+	var buf bytes.Buffer
+	if err := game.WriteSnapshot(&buf); err != nil {
+		t.Fatal(err)
+	}
+	reader := bytes.NewReader(buf.Bytes())
+
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil || string(header[:4]) != "GLSN" || header[4] != 1 {
+		t.Fatalf("Bad header %q", header)
+	}
+
+	sections := make(map[byte][][]byte)
+	for {
+		kind, err := reader.ReadByte()
+		if err != nil {
+			t.Fatal("Snapshot ended without an end section")
+		}
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload := make([]byte, length)
+		var sum [4]byte
+		if _, err := io.ReadFull(reader, payload); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadFull(reader, sum[:]); err != nil {
+			t.Fatal(err)
+		}
+		if binary.BigEndian.Uint32(sum[:]) != crc32.ChecksumIEEE(payload) {
+			t.Errorf("Section %d has checksum %x, expected %x", kind, sum, crc32.ChecksumIEEE(payload))
+		}
+		if kind == 0 {
+			break
+		}
+		sections[kind] = append(sections[kind], payload)
+	}
+	if reader.Len() != 0 {
+		t.Errorf("%d bytes after the end section", reader.Len())
+	}
+
+	// The population section is a count and then each cell as a varint
+	// offset from the one before, Y first.
+	cells := bytes.NewReader(sections[2][0])
+	count, _ := binary.ReadUvarint(cells)
+	pop := make(golife.Population)
+	var cell golife.Cell
+	for range count {
+		dy, _ := binary.ReadVarint(cells)
+		dx, _ := binary.ReadVarint(cells)
+		cell.Y += golife.Coord(dy)
+		cell.X += golife.Coord(dx)
+		pop[cell] = true
+	}
+	if match, errmsg := samePop(game.Population, pop); !match {
+		t.Errorf("Decoded population differs: %s", errmsg)
+	}
+
+	// The meta section ends with the cycle detection hashes, the newest
+	// being that of the current population.
+	meta := sections[1][0]
+	if len(meta) < 8 {
+		t.Fatal("Meta section too short")
+	}
+	if hash := binary.BigEndian.Uint64(meta[len(meta)-8:]); hash != pop.Hash() {
+		t.Errorf("Newest hash %x doesn't match the population's %x", hash, pop.Hash())
+	}
+}
@@ -0,0 +1,271 @@
+package golife
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	soup_size            = 16
+	soup_max_generations = 20000
+	soup_hash_window     = max_census_period
+	soup_check_interval  = 4 * soup_hash_window
+	soup_escape_margin   = 4
+	escape_max_size      = 256
+)
+
+// SoupFromSeed generates the 16x16 soup used by apgsearch and Catagolue for
+// a seed string: the SHA-256 digest of the seed, two bytes per row, most
+// significant bit leftmost.  It's the C1 FillSoup at density one half.
+func SoupFromSeed(seed string) Population {
+	game := NewGame()
+	game.FillSoup(Cell{0, 0}, Cell{soup_size - 1, soup_size - 1}, 0.5, C1, seed)
+	return game.Population
+}
+
+// Stabilize runs the population until its hash repeats within the last
+// few generations, or until maxGenerations have gone by.  It returns the
+// final population, the number of generations run and whether it settled.
+func Stabilize(pop Population, maxGenerations int) (Population, int, bool) {
+	hashes := make([]uint64, 0, soup_hash_window)
+	for gen := 0; gen < maxGenerations; gen++ {
+		hash := pop.Hash()
+		for i := range hashes {
+			if hashes[i] == hash {
+				return pop, gen, true
+			}
+		}
+		if len(hashes) >= soup_hash_window {
+			hashes = hashes[1:]
+		}
+		hashes = append(hashes, hash)
+		pop = pop.Step()
+	}
+	return pop, maxGenerations, false
+}
+
+type SoupResults struct {
+	Soups        int
+	Counts       map[string]int
+	Rare         map[string][]string
+	Unclassified []string
+	Unsettled    []string
+}
+
+func NewSoupResults() *SoupResults {
+	var results SoupResults
+	results.Counts = make(map[string]int)
+	results.Rare = make(map[string][]string)
+	results.Unclassified = make([]string, 0)
+	results.Unsettled = make([]string, 0)
+	return &results
+}
+
+// settleSoup runs a soup until it stabilizes, taking out spaceships that
+// have left the rest of the pattern behind, since they would otherwise keep
+// the population from ever repeating.
+func settleSoup(pop Population) (Population, []Population, bool) {
+	escaped := make([]Population, 0)
+	for gen := 0; gen < soup_max_generations; gen += soup_check_interval {
+		var settled bool
+		pop, _, settled = Stabilize(pop, soup_check_interval)
+		if settled {
+			return pop, escaped, true
+		}
+		for _, object := range escapingObjects(pop) {
+			for cell := range object {
+				delete(pop, cell)
+			}
+			escaped = append(escaped, object)
+		}
+	}
+	return pop, escaped, false
+}
+
+// extents keeps the number of live cells in each row and column of a
+// population, so the bounding box of everything but one object can be
+// found without copying the rest of the population.
+type extents struct {
+	rows, cols map[Coord]int
+	ys, xs     []Coord
+}
+
+func newExtents(pop Population) *extents {
+	var e extents
+	e.rows = make(map[Coord]int)
+	e.cols = make(map[Coord]int)
+	for cell, present := range pop {
+		if present {
+			e.rows[cell.Y] += 1
+			e.cols[cell.X] += 1
+		}
+	}
+	e.ys = slices.Sorted(maps.Keys(e.rows))
+	e.xs = slices.Sorted(maps.Keys(e.cols))
+	return &e
+}
+
+// without returns the bounding box of the population less the object, and
+// false if there is nothing left.  Only the rows and columns at either end
+// that the object accounts for are looked at.
+func (e *extents) without(object Population) (Cell, Cell, bool) {
+	objRows := make(map[Coord]int)
+	objCols := make(map[Coord]int)
+	for cell := range object {
+		objRows[cell.Y] += 1
+		objCols[cell.X] += 1
+	}
+
+	first := func(keys []Coord, counts, taken map[Coord]int, step int) (Coord, bool) {
+		i := 0
+		if step < 0 {
+			i = len(keys) - 1
+		}
+		for ; i >= 0 && i < len(keys); i += step {
+			if counts[keys[i]] > taken[keys[i]] {
+				return keys[i], true
+			}
+		}
+		return 0, false
+	}
+	min_y, found := first(e.ys, e.rows, objRows, 1)
+	if !found {
+		return Cell{}, Cell{}, false
+	}
+	max_y, _ := first(e.ys, e.rows, objRows, -1)
+	min_x, _ := first(e.xs, e.cols, objCols, 1)
+	max_x, _ := first(e.xs, e.cols, objCols, -1)
+	return Cell{min_x, min_y}, Cell{max_x, max_y}, true
+}
+
+// edgeObjects returns the objects, clustered the same way as Objects, that
+// have a cell on the edge of the population's bounding box, since anything
+// leaving the pattern has to be out there.  Clusters bigger than
+// escape_max_size are given up on, as no spaceship that big turns up.
+func edgeObjects(pop Population) []Population {
+	objects := make([]Population, 0)
+	if len(pop) == 0 {
+		return objects
+	}
+	min_cell, max_cell := pop.BoundingBox()
+	seen := make(Population)
+
+	for start, present := range pop {
+		if !present || seen[start] {
+			continue
+		}
+		if start.X != min_cell.X && start.X != max_cell.X && start.Y != min_cell.Y && start.Y != max_cell.Y {
+			continue
+		}
+		object := make(Population)
+		queue := []Cell{start}
+		seen[start] = true
+		for len(queue) > 0 && len(object) <= escape_max_size {
+			cell := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			object[cell] = true
+			for dy := Coord(-2); dy <= 2; dy++ {
+				for dx := Coord(-2); dx <= 2; dx++ {
+					neighbor := Cell{cell.X + dx, cell.Y + dy}
+					if pop[neighbor] && !seen[neighbor] {
+						seen[neighbor] = true
+						queue = append(queue, neighbor)
+					}
+				}
+			}
+		}
+		if len(queue) == 0 {
+			objects = append(objects, object)
+		}
+	}
+	return objects
+}
+
+// escapingObjects returns the spaceships in the population that are beyond
+// the rest of it and moving away, so they can never interact again.
+func escapingObjects(pop Population) []Population {
+	candidates := edgeObjects(pop)
+	if len(candidates) == 0 {
+		return candidates
+	}
+
+	e := newExtents(pop)
+	escaped := make([]Population, 0)
+	for _, object := range candidates {
+		rest_min, rest_max, rest := e.without(object)
+		if escaping(object, rest_min, rest_max, rest) {
+			escaped = append(escaped, object)
+		}
+	}
+	return escaped
+}
+
+// escaping reports whether object is a spaceship beyond the bounding box
+// of the rest of the population and moving away from it.  Objects that
+// aren't clear of the rest are ruled out before running them to classify
+// them.
+func escaping(object Population, rest_min, rest_max Cell, rest bool) bool {
+	obj_min, obj_max := object.BoundingBox()
+	beyond_x := obj_min.X > rest_max.X+soup_escape_margin || obj_max.X < rest_min.X-soup_escape_margin
+	beyond_y := obj_min.Y > rest_max.Y+soup_escape_margin || obj_max.Y < rest_min.Y-soup_escape_margin
+	if rest && !beyond_x && !beyond_y {
+		return false
+	}
+
+	class := object.Classify(max_census_period)
+	if class.Type != Spaceship {
+		return false
+	}
+	if !rest {
+		return true
+	}
+	return class.Dx > 0 && obj_min.X > rest_max.X+soup_escape_margin ||
+		class.Dx < 0 && obj_max.X < rest_min.X-soup_escape_margin ||
+		class.Dy > 0 && obj_min.Y > rest_max.Y+soup_escape_margin ||
+		class.Dy < 0 && obj_max.Y < rest_min.Y-soup_escape_margin
+}
+
+// AddSoup runs the soup for a seed to stabilization and adds its census to
+// the results, remembering the seed for any rare objects it produced.
+func (results *SoupResults) AddSoup(seed string) {
+	pop, escaped, settled := settleSoup(SoupFromSeed(seed))
+	results.Soups += 1
+	if !settled {
+		results.Unsettled = append(results.Unsettled, seed)
+	}
+
+	counts, unclassified := Census(pop)
+	for _, object := range escaped {
+		counts[object.Apgcode()] += 1
+	}
+	for code, count := range counts {
+		results.Counts[code] += count
+		if isRare(code) {
+			results.Rare[code] = append(results.Rare[code], seed)
+		}
+	}
+	if len(unclassified) > 0 {
+		results.Unclassified = append(results.Unclassified, seed)
+	}
+}
+
+// SearchSoups runs count soups with seeds made of the prefix followed by
+// a sequence number, starting at zero.
+func SearchSoups(prefix string, count int) *SoupResults {
+	results := NewSoupResults()
+	for i := 0; i < count; i++ {
+		results.AddSoup(fmt.Sprintf("%s%d", prefix, i))
+	}
+	return results
+}
+
+// Spaceships other than the ubiquitous glider, and anything oscillating with
+// a period above two, are rare enough to be worth keeping the seed for.
+func isRare(code string) bool {
+	if strings.HasPrefix(code, "xq") {
+		return code != "xq4_153"
+	}
+	return strings.HasPrefix(code, "xp") && !strings.HasPrefix(code, "xp2_")
+}
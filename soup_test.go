@@ -0,0 +1,46 @@
+package golife_test
+
+import (
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestSoupFromSeed(t *testing.T) {
+	soup := golife.SoupFromSeed("k_test0")
+	again := golife.SoupFromSeed("k_test0")
+	if match, errmsg := cmpPops(soup, again); !match {
+		t.Errorf("Same seed gave different soups: %s", errmsg)
+	}
+
+	min_cell, max_cell := soup.BoundingBox()
+	if min_cell.X < 0 || min_cell.Y < 0 || max_cell.X > 15 || max_cell.Y > 15 {
+		t.Errorf("Soup outside 16x16 square: %v -> %v", min_cell, max_cell)
+	}
+}
+
+func TestStabilize(t *testing.T) {
+	blinker := popFromCells(testPattern)
+	_, gens, settled := golife.Stabilize(blinker, 10)
+	if !settled || gens != 2 {
+		t.Errorf("Blinker should settle after 2 generations, got %d (%v)", gens, settled)
+	}
+
+	glider := popFromCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	if _, _, settled := golife.Stabilize(glider, 100); settled {
+		t.Error("Glider shouldn't settle")
+	}
+}
+
+func TestSearchSoups(t *testing.T) {
+	results := golife.SearchSoups("k_test", 5)
+	if results.Soups != 5 {
+		t.Errorf("Expected 5 soups, got %d", results.Soups)
+	}
+	if len(results.Unsettled) != 0 {
+		t.Errorf("Unexpected unsettled soups %v", results.Unsettled)
+	}
+	if results.Counts["xs4_33"] == 0 {
+		t.Errorf("No blocks in census %v", results.Counts)
+	}
+}
@@ -0,0 +1,77 @@
+package golife
+
+type Status int
+
+const (
+	Running Status = iota
+	Extinct
+	Static
+	Periodic
+)
+
+func (status Status) String() string {
+	switch status {
+	case Extinct:
+		return "extinct"
+	case Static:
+		return "still life"
+	case Periodic:
+		return "periodic"
+	default:
+		return "running"
+	}
+}
+
+// SetCycleDetection makes Next look for the population repeating with a
+// period of up to maxPeriod generations.  Only the hashes of the last
+// maxPeriod generations are kept, not the populations themselves.  A
+// maxPeriod of 0 turns detection off, leaving only extinction reported.
+func (game *Game) SetCycleDetection(maxPeriod int) {
+	game.cycleLimit = maxPeriod
+	game.resetStatus()
+}
+
+// Status returns what the last call to Next found, along with the period
+// when the population is cycling (1 for a still life).
+func (game *Game) Status() (Status, int) {
+	return game.status, game.period
+}
+
+func (game *Game) resetStatus() {
+	game.status = Running
+	game.period = 0
+	game.hashes = nil
+}
+
+func (game *Game) updateStatus() Status {
+	game.period = 0
+	if game.Population.Size() == 0 {
+		game.status = Extinct
+		game.hashes = nil
+		return game.status
+	}
+	game.status = Running
+	if game.cycleLimit <= 0 {
+		game.hashes = nil
+		return game.status
+	}
+
+	hash := game.Population.Hash()
+	for i := len(game.hashes) - 1; i >= 0; i-- {
+		if game.hashes[i] == hash {
+			game.period = len(game.hashes) - i
+			if game.period == 1 {
+				game.status = Static
+			} else {
+				game.status = Periodic
+			}
+			break
+		}
+	}
+
+	if len(game.hashes) >= game.cycleLimit {
+		game.hashes = append(game.hashes[:0], game.hashes[len(game.hashes)-game.cycleLimit+1:]...)
+	}
+	game.hashes = append(game.hashes, hash)
+	return game.status
+}
@@ -0,0 +1,61 @@
+package golife_test
+
+import (
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestExtinction(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCell(golife.Cell{0, 0})
+	if status := game.Next(); status != golife.Extinct {
+		t.Errorf("Lone cell should die out, got status %v", status)
+	}
+}
+
+func TestCycleDetection(t *testing.T) {
+	game := golife.NewGame()
+	game.SetCycleDetection(4)
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}})
+	game.Next()
+	if status, period := game.Status(); status != golife.Static || period != 1 {
+		t.Errorf("Block should be a still life, got %v (%d)", status, period)
+	}
+
+	game = golife.NewGame()
+	game.SetCycleDetection(4)
+	game.AddCells(testPattern)
+	if status := game.Next(); status != golife.Running {
+		t.Errorf("Blinker reported %v before completing a cycle", status)
+	}
+	game.Next()
+	if status, period := game.Status(); status != golife.Periodic || period != 2 {
+		t.Errorf("Blinker should have period 2, got %v (%d)", status, period)
+	}
+
+	game.AddCell(golife.Cell{10, 10})
+	if status, _ := game.Status(); status != golife.Running {
+		t.Errorf("Editing the population should reset the status, got %v", status)
+	}
+}
+
+func TestCycleDetectionLimit(t *testing.T) {
+	game := golife.NewGame()
+	game.SetCycleDetection(1)
+	game.AddCells(testPattern)
+	for range 4 {
+		if status := game.Next(); status != golife.Running {
+			t.Errorf("Blinker period is beyond the detection limit, got %v", status)
+		}
+	}
+
+	glider := golife.NewGame()
+	glider.SetCycleDetection(8)
+	glider.AddCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+	for range 20 {
+		if status := glider.Next(); status != golife.Running {
+			t.Errorf("Glider never repeats in place, got %v", status)
+		}
+	}
+}
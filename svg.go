@@ -0,0 +1,135 @@
+package golife
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type SVGOptions struct {
+	CellSize int
+	Margin   int
+	Alive    string
+
+	// Overlays, drawn on top of the cells.  GridSpacing draws a line every
+	// that many cells, labelled with its coordinate, and Labels puts the
+	// apgcode of each object it can identify above it.
+	BoundingBox bool
+	GridSpacing int
+	Labels      bool
+	Metadata    bool
+}
+
+func DefaultSVGOptions() SVGOptions {
+	var opts SVGOptions
+	opts.CellSize = 10
+	opts.Margin = 2
+	opts.Alive = "black"
+	return opts
+}
+
+func escapeXML(text string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(text))
+	return escaped.String()
+}
+
+func (game *Game) SaveSVG(filepath string, opts SVGOptions) error {
+	fileWriter, err := os.Create(filepath)
+	if err != nil {
+		return err
+	}
+	defer fileWriter.Close()
+	return game.WriteSVG(fileWriter, opts)
+}
+
+// WriteSVG draws the live cells as a single path, merging each run of live
+// cells in a row into one rectangle to keep the file small.
+func (game *Game) WriteSVG(outfile io.Writer, opts SVGOptions) error {
+	if opts.CellSize < 1 {
+		opts.CellSize = DefaultSVGOptions().CellSize
+	}
+	if opts.Alive == "" {
+		opts.Alive = DefaultSVGOptions().Alive
+	}
+
+	min_cell, max_cell := game.BoundingBox()
+	if min_cell.X > max_cell.X {
+		min_cell, max_cell = Cell{0, 0}, Cell{0, 0}
+	}
+	size := Coord(opts.CellSize)
+	header := Coord(0)
+	if opts.Metadata && (game.Name != "" || game.Author != "") {
+		header = 2
+	}
+	origin := Cell{min_cell.X - Coord(opts.Margin), min_cell.Y - Coord(opts.Margin) - header}
+	width := (max_cell.X - origin.X + 1 + Coord(opts.Margin)) * size
+	height := (max_cell.Y - origin.Y + 1 + Coord(opts.Margin)) * size
+	px := func(x Coord) Coord { return (x - origin.X) * size }
+	py := func(y Coord) Coord { return (y - origin.Y) * size }
+
+	out := bufio.NewWriter(outfile)
+	fmt.Fprintf(out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	if game.Name != "" {
+		fmt.Fprintf(out, "<title>%s</title>\n", escapeXML(game.Name))
+	}
+	fmt.Fprintf(out, "<rect width=\"%d\" height=\"%d\" fill=\"white\"/>\n", width, height)
+
+	fmt.Fprintf(out, "<path fill=\"%s\" transform=\"scale(%d) translate(%d %d)\" d=\"", escapeXML(opts.Alive), size, -origin.X, -origin.Y)
+	for y, row := range game.Population.Rows() {
+		for start := 0; start < len(row); {
+			end := start + 1
+			for end < len(row) && row[end].X == row[end-1].X+1 {
+				end += 1
+			}
+			fmt.Fprintf(out, "M%d %dh%dv1h-%dz", row[start].X, y, end-start, end-start)
+			start = end
+		}
+	}
+	fmt.Fprint(out, "\"/>\n")
+
+	if opts.GridSpacing > 0 {
+		spacing := Coord(opts.GridSpacing)
+		fmt.Fprint(out, "<g stroke=\"#ccc\" stroke-width=\"1\" font-size=\"8\" fill=\"#888\" font-family=\"sans-serif\">\n")
+		for x := min_cell.X - min_cell.X%spacing; x <= max_cell.X+1; x += spacing {
+			fmt.Fprintf(out, "<line x1=\"%d\" y1=\"0\" x2=\"%d\" y2=\"%d\"/><text x=\"%d\" y=\"%d\" stroke=\"none\">%d</text>\n", px(x), px(x), height, px(x)+1, height-1, x)
+		}
+		for y := min_cell.Y - min_cell.Y%spacing; y <= max_cell.Y+1; y += spacing {
+			fmt.Fprintf(out, "<line x1=\"0\" y1=\"%d\" x2=\"%d\" y2=\"%d\"/><text x=\"1\" y=\"%d\" stroke=\"none\">%d</text>\n", py(y), width, py(y), py(y)-1, y)
+		}
+		fmt.Fprint(out, "</g>\n")
+	}
+
+	if opts.BoundingBox && game.Size() > 0 {
+		fmt.Fprintf(out, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"none\" stroke=\"red\" stroke-width=\"1\"/>\n",
+			px(min_cell.X), py(min_cell.Y), (max_cell.X-min_cell.X+1)*size, (max_cell.Y-min_cell.Y+1)*size)
+	}
+
+	if opts.Labels {
+		fmt.Fprint(out, "<g font-size=\"10\" fill=\"blue\" font-family=\"sans-serif\">\n")
+		for _, object := range game.Population.SplitObjects() {
+			if code := object.Apgcode(); code != "" {
+				object_min, _ := object.BoundingBox()
+				fmt.Fprintf(out, "<text x=\"%d\" y=\"%d\">%s</text>\n", px(object_min.X), py(object_min.Y)-2, escapeXML(code))
+			}
+		}
+		fmt.Fprint(out, "</g>\n")
+	}
+
+	if header > 0 {
+		text := game.Name
+		if game.Author != "" {
+			if text != "" {
+				text += " "
+			}
+			text += "by " + game.Author
+		}
+		fmt.Fprintf(out, "<text x=\"%d\" y=\"%d\" font-size=\"%d\" font-family=\"sans-serif\">%s</text>\n", size, size*3/2, size, escapeXML(text))
+	}
+
+	fmt.Fprint(out, "</svg>\n")
+	return out.Flush()
+}
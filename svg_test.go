@@ -0,0 +1,59 @@
+package golife_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func wellFormed(t *testing.T, svg string) {
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Invalid SVG: %v\n%s", err, svg)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	game.AddCells(golife.CellList{{10, 10}, {11, 10}, {10, 11}, {11, 11}})
+	game.Name = "Blinker & block"
+	game.Author = "<someone>"
+
+	opts := golife.DefaultSVGOptions()
+	opts.BoundingBox = true
+	opts.GridSpacing = 5
+	opts.Labels = true
+	opts.Metadata = true
+
+	var buf bytes.Buffer
+	if err := game.WriteSVG(&buf, opts); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	wellFormed(t, svg)
+
+	for _, expected := range []string{"M0 0h3v1h-3z", "M10 10h2v1h-2zM10 11h2v1h-2z", ">xp2_7<", ">xs4_33<", "Blinker &amp; block by &lt;someone&gt;"} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("SVG missing %q:\n%s", expected, svg)
+		}
+	}
+}
+
+func TestWriteSVGEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := golife.NewGame().WriteSVG(&buf, golife.SVGOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	wellFormed(t, buf.String())
+}
@@ -0,0 +1,173 @@
+package golife
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+type TerminalMode int
+
+const (
+	HalfBlocks TerminalMode = iota
+	Braille
+)
+
+type TerminalOptions struct {
+	Mode TerminalMode
+
+	// Width and Height are the space available in characters, 0 meaning no
+	// limit.  Scale is how many cells across each dot covers, a dot being
+	// lit when any cell under it is alive.  If Scale is 0 the smallest one
+	// that fits is used.
+	Width, Height int
+	Scale         int
+
+	// With Color set, dots are colored by their Heat, the largest value
+	// of the live cells under them, which could for example be how many
+	// generations each cell has been alive.
+	Color bool
+	Heat  map[Cell]int
+}
+
+// heat_colors goes from green through yellow to red in the xterm 256 color
+// palette.
+var heat_colors = []int{46, 82, 118, 154, 190, 226, 220, 214, 208, 202, 196}
+
+func (mode TerminalMode) dotsPerChar() (int, int) {
+	if mode == Braille {
+		return 2, 4
+	}
+	return 1, 2
+}
+
+// braille_bits maps a dot within a 2x4 braille character to its bit in the
+// character's offset from U+2800.
+var braille_bits = [4][2]rune{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// FitScale returns the smallest Scale at which the rectangle fits in the
+// options' Width and Height.
+func (opts TerminalOptions) FitScale(min_cell, max_cell Cell) int {
+	dx, dy := opts.Mode.dotsPerChar()
+	width, height := int64(max_cell.X-min_cell.X+1), int64(max_cell.Y-min_cell.Y+1)
+	scale := int64(1)
+	if opts.Width > 0 {
+		scale = max(scale, (width+int64(opts.Width*dx)-1)/int64(opts.Width*dx))
+	}
+	if opts.Height > 0 {
+		scale = max(scale, (height+int64(opts.Height*dy)-1)/int64(opts.Height*dy))
+	}
+	return int(scale)
+}
+
+// WriteTerminal draws the part of the population from min_cell to max_cell
+// using Unicode half blocks, two dots to a character one above the other,
+// or braille, with 2x4 dots to a character.
+func WriteTerminal(outfile io.Writer, pop Population, min_cell, max_cell Cell, opts TerminalOptions) error {
+	if min_cell.X > max_cell.X || min_cell.Y > max_cell.Y {
+		return nil
+	}
+	scale := opts.Scale
+	if scale < 1 {
+		scale = opts.FitScale(min_cell, max_cell)
+	}
+	dx, dy := opts.Mode.dotsPerChar()
+	dots_x := int((int64(max_cell.X-min_cell.X) + int64(scale)) / int64(scale))
+	dots_y := int((int64(max_cell.Y-min_cell.Y) + int64(scale)) / int64(scale))
+	if opts.Width > 0 {
+		dots_x = min(dots_x, opts.Width*dx)
+	}
+	if opts.Height > 0 {
+		dots_y = min(dots_y, opts.Height*dy)
+	}
+	cols, rows := (dots_x+dx-1)/dx, (dots_y+dy-1)/dy
+
+	// Each dot holds 0 when dark, otherwise 1 plus the hottest heat under it.
+	dots := make([]int, cols*dx*rows*dy)
+	stride := cols * dx
+	for cell, present := range pop {
+		if !present || cell.X < min_cell.X || cell.X > max_cell.X || cell.Y < min_cell.Y || cell.Y > max_cell.Y {
+			continue
+		}
+		x, y := int(int64(cell.X-min_cell.X)/int64(scale)), int(int64(cell.Y-min_cell.Y)/int64(scale))
+		if x < dots_x && y < dots_y {
+			dots[y*stride+x] = max(dots[y*stride+x], 1+opts.Heat[cell])
+		}
+	}
+
+	color := func(dot int) int {
+		return heat_colors[min(dot-1, len(heat_colors)-1)]
+	}
+
+	out := bufio.NewWriter(outfile)
+	current := ""
+	setAttrs := func(attrs string) {
+		if attrs != current {
+			out.WriteString(attrs)
+			current = attrs
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if opts.Mode == Braille {
+				var char rune = 0x2800
+				hottest := 0
+				for y := 0; y < 4; y++ {
+					for x := 0; x < 2; x++ {
+						if dot := dots[(row*4+y)*stride+col*2+x]; dot > 0 {
+							char |= braille_bits[y][x]
+							hottest = max(hottest, dot)
+						}
+					}
+				}
+				if opts.Color {
+					attrs := "\x1b[0m"
+					if hottest > 0 {
+						attrs = fmt.Sprintf("\x1b[0;38;5;%dm", color(hottest))
+					}
+					setAttrs(attrs)
+				}
+				out.WriteRune(char)
+				continue
+			}
+
+			top, bottom := dots[row*2*stride+col], dots[(row*2+1)*stride+col]
+			switch {
+			case opts.Color:
+				attrs := "\x1b[0"
+				if top > 0 {
+					attrs += fmt.Sprintf(";38;5;%d", color(top))
+				}
+				if bottom > 0 {
+					attrs += fmt.Sprintf(";48;5;%d", color(bottom))
+				}
+				setAttrs(attrs + "m")
+				if top > 0 {
+					out.WriteRune('▀')
+				} else {
+					out.WriteRune(' ')
+				}
+			case top > 0 && bottom > 0:
+				out.WriteRune('█')
+			case top > 0:
+				out.WriteRune('▀')
+			case bottom > 0:
+				out.WriteRune('▄')
+			default:
+				out.WriteRune(' ')
+			}
+		}
+		if opts.Color && current != "\x1b[0m" {
+			setAttrs("\x1b[0m")
+		}
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+// WriteTerminal draws the whole pattern, scaled down as needed to fit.
+func (game *Game) WriteTerminal(outfile io.Writer, opts TerminalOptions) error {
+	min_cell, max_cell := game.BoundingBox()
+	return WriteTerminal(outfile, game.Population, min_cell, max_cell, opts)
+}
@@ -0,0 +1,57 @@
+package golife_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func TestWriteTerminalHalfBlocks(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{1, 0}, {2, 1}, {0, 2}, {1, 2}, {2, 2}})
+
+	var buf bytes.Buffer
+	if err := game.WriteTerminal(&buf, golife.TerminalOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := " ▀▄\n▀▀▀\n"; buf.String() != expected {
+		t.Errorf("Expected\n%q\ngot\n%q", expected, buf.String())
+	}
+}
+
+func TestWriteTerminalBraille(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(golife.CellList{{0, 0}, {1, 0}, {0, 1}, {1, 1}})
+
+	var buf bytes.Buffer
+	game.WriteTerminal(&buf, golife.TerminalOptions{Mode: golife.Braille})
+	if expected := "⠛\n"; buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+func TestWriteTerminalScaling(t *testing.T) {
+	game := golife.NewGame()
+	game.FillRect(golife.Cell{0, 0}, golife.Cell{399, 9})
+
+	opts := golife.TerminalOptions{Width: 80}
+	if scale := opts.FitScale(game.BoundingBox()); scale != 5 {
+		t.Errorf("Expected scale 5 to fit 400 cells in 80 columns, got %d", scale)
+	}
+
+	var buf bytes.Buffer
+	game.WriteTerminal(&buf, opts)
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 1 || len([]rune(lines[0])) != 80 {
+		t.Errorf("Expected one line of 80 characters, got %q", lines)
+	}
+
+	buf.Reset()
+	opts.Color = true
+	game.WriteTerminal(&buf, opts)
+	if !strings.Contains(buf.String(), "38;5;46") {
+		t.Errorf("Expected colored output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,213 @@
+package golife
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// timeline keeps sparse keyframes over the whole run of a game, so GoTo can
+// get back to any generation by re-running from the nearest one.  When
+// there are more than limit keyframes, every other one is dropped and the
+// interval doubles, so memory stays bounded however long the game runs.
+// The keyframes at generations that were edited are kept through that, as
+// re-running from an earlier one wouldn't bring the edits back.
+type timeline struct {
+	origin    int
+	interval  int
+	limit     int
+	dirty     bool
+	edits     []int
+	keyframes map[int]Population
+}
+
+// SetTimeline keeps a keyframe every interval generations from the current
+// one onward, up to maxKeyframes of them, trading memory against how many
+// generations GoTo may have to re-run when going backward.  An interval of
+// 0 turns the timeline off.
+func (game *Game) SetTimeline(interval, maxKeyframes int) {
+	if interval <= 0 {
+		game.timeline = nil
+		return
+	}
+	if maxKeyframes < 2 {
+		maxKeyframes = 2
+	}
+
+	var tl timeline
+	tl.origin = game.Generation
+	tl.interval = interval
+	tl.limit = maxKeyframes
+	tl.keyframes = make(map[int]Population)
+	tl.keyframes[game.Generation] = game.Population.copy()
+	game.timeline = &tl
+}
+
+func (game *Game) recordKeyframe() {
+	game.timeline.record(game.Population, game.Generation)
+}
+
+func (tl *timeline) record(pop Population, generation int) {
+	if tl == nil || (generation-tl.origin)%tl.interval != 0 {
+		return
+	}
+	tl.keyframes[generation] = pop.copy()
+	for len(tl.keyframes)-len(tl.edits) > tl.limit {
+		tl.interval *= 2
+		for generation := range tl.keyframes {
+			if (generation-tl.origin)%tl.interval != 0 && !slices.Contains(tl.edits, generation) {
+				delete(tl.keyframes, generation)
+			}
+		}
+	}
+}
+
+// editTimeline forgets keyframes that an edit to the current population has
+// made stale.  The edited population itself is saved at the next step.
+func (game *Game) editTimeline() {
+	tl := game.timeline
+	if tl == nil {
+		return
+	}
+	for generation := range tl.keyframes {
+		if generation >= game.Generation {
+			delete(tl.keyframes, generation)
+		}
+	}
+	if len(tl.edits) == 0 || tl.edits[len(tl.edits)-1] != game.Generation {
+		tl.edits = append(tl.edits, game.Generation)
+	}
+	tl.dirty = true
+}
+
+// rewindTimeline is called whenever the game goes back a generation or
+// more.  Edits made after the new generation are no longer in the game's
+// past, so the keyframes from the first of them on belong to a branch that
+// has been abandoned, and are dropped.
+func (game *Game) rewindTimeline() {
+	tl := game.timeline
+	if tl == nil {
+		return
+	}
+	kept := len(tl.edits)
+	for kept > 0 && tl.edits[kept-1] > game.Generation {
+		kept -= 1
+	}
+	if kept == len(tl.edits) {
+		return
+	}
+	branch := tl.edits[kept]
+	tl.edits = tl.edits[:kept]
+	for generation := range tl.keyframes {
+		if generation >= branch {
+			delete(tl.keyframes, generation)
+		}
+	}
+}
+
+func (game *Game) saveEditedKeyframe() {
+	tl := game.timeline
+	if tl == nil || !tl.dirty {
+		return
+	}
+	tl.keyframes[game.Generation] = game.Population.copy()
+	tl.dirty = false
+}
+
+// resetHistory drops the step by step history, for when the game jumps to
+// a generation the history can't connect with.
+func (game *Game) resetHistory() {
+	if game.History != nil {
+		game.History = game.History[:0]
+	}
+	if game.deltas != nil {
+		game.deltas = newDeltaHistory(game.deltas.interval, game.Population, game.Generation)
+	}
+	game.resetStatus()
+}
+
+// jumpTo moves the game straight to a population and generation that its
+// history doesn't connect with, recording it in the journal along with the
+// timeline as it was, so Undo can put both back.
+func (game *Game) jumpTo(pop Population, generation int) {
+	from := game.Generation
+	if game.journal != nil {
+		delta := diffPopulations(game.Population, pop)
+		game.journalOp("jump", journalOp{kind: jumpOp, births: delta.births, deaths: delta.deaths,
+			from: from, to: generation, timeline: game.timeline.clone()})
+	}
+	game.Population = pop
+	game.Generation = generation
+	if generation < from {
+		game.rewindTimeline()
+	}
+	game.invalidateIndex()
+	game.resetHistory()
+	game.fire(JumpEvent, from, nil, nil)
+}
+
+// clone copies the timeline's bookkeeping.  The keyframes themselves are
+// never changed once made, so they are shared.
+func (tl *timeline) clone() *timeline {
+	if tl == nil {
+		return nil
+	}
+	copied := *tl
+	copied.edits = slices.Clone(tl.edits)
+	copied.keyframes = maps.Clone(tl.keyframes)
+	return &copied
+}
+
+// GoTo moves the game to any generation.  Going forward, generations that
+// won't be kept in the history are stepped without recording them, and
+// once the game is known to be cycling it skips whole periods.  Going
+// backward, the history is used if it reaches far enough, otherwise the game
+// is re-run from the nearest earlier timeline keyframe.  With the journal
+// on, the whole move is undone as one entry.
+func (game *Game) GoTo(generation int) error {
+	game.saveEditedKeyframe()
+	game.BeginEdit("go to")
+	defer game.EndEdit()
+
+	if generation < game.Generation {
+		if game.SeekTo(generation) == nil {
+			return nil
+		}
+		tl := game.timeline
+		if tl == nil {
+			return errors.New("Generation is not in history or timeline")
+		}
+		keyframe := -1
+		for kf := range tl.keyframes {
+			if kf <= generation && (keyframe < 0 || kf > keyframe) {
+				keyframe = kf
+			}
+		}
+		if keyframe < 0 {
+			return errors.New("Generation is not in history or timeline")
+		}
+		game.jumpTo(tl.keyframes[keyframe].copy(), keyframe)
+	}
+
+	if status, period := game.Status(); status == Static || status == Periodic {
+		skip := (generation - game.Generation) / period * period
+		if skip > 0 {
+			game.jumpTo(game.Population, game.Generation+skip)
+		}
+	}
+
+	if game.HistorySize >= 0 && generation-game.Generation > game.HistorySize {
+		pop, at := game.Population, game.Generation
+		for generation-at > game.HistorySize {
+			pop = pop.Step()
+			at += 1
+			game.timeline.record(pop, at)
+		}
+		game.jumpTo(pop, at)
+	}
+
+	for game.Generation < generation {
+		game.Next()
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package golife_test
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/pneumaticdeath/golife"
+)
+
+func popAt(pop golife.Population, generation int) golife.Population {
+	for range generation {
+		pop = pop.Step()
+	}
+	return pop
+}
+
+func TestGoTo(t *testing.T) {
+	soup := golife.SoupFromSeed("k_test0")
+	game := golife.NewGame()
+	game.Population = soup
+	game.SetHistorySize(0)
+	game.SetTimeline(10, 4)
+
+	if err := game.GoTo(200); err != nil || game.Generation != 200 {
+		t.Fatalf("GoTo forward failed: %v at generation %d", err, game.Generation)
+	}
+	if match, errmsg := samePop(popAt(soup, 200), game.Population); !match {
+		t.Errorf("Wrong population at generation 200: %s", errmsg)
+	}
+
+	for _, generation := range []int{37, 150, 0, 199} {
+		if err := game.GoTo(generation); err != nil || game.Generation != generation {
+			t.Fatalf("GoTo %d failed: %v at generation %d", generation, err, game.Generation)
+		}
+		if match, errmsg := samePop(popAt(soup, generation), game.Population); !match {
+			t.Errorf("Wrong population at generation %d: %s", generation, errmsg)
+		}
+	}
+}
+
+func TestGoToWithoutTimeline(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	game.SetHistorySize(2)
+	game.GoTo(10)
+	if err := game.GoTo(8); err != nil {
+		t.Errorf("Generation 8 should be in history: %v", err)
+	}
+	if err := game.GoTo(1); err == nil {
+		t.Error("Went back beyond history without a timeline")
+	}
+}
+
+func TestGoToAfterEdit(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	game.SetHistorySize(0)
+	game.SetTimeline(4, 10)
+	game.GoTo(10)
+	game.AddCells(golife.CellList{{10, 10}, {11, 10}, {10, 11}, {11, 11}})
+	game.GoTo(20)
+	game.GoTo(15)
+	if game.Size() != 7 {
+		t.Errorf("Edit was lost going back in time, population is %d", game.Size())
+	}
+	game.GoTo(5)
+	if game.Size() != 3 {
+		t.Errorf("Edit leaked to before it was made, population is %d", game.Size())
+	}
+}
+
+func TestGoToAbandonsEdit(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	game.SetCycleDetection(4)
+	game.SetHistorySize(0)
+	game.SetTimeline(4, 20)
+	game.GoTo(10)
+	game.AddCells(golife.CellList{{10, 10}, {11, 10}, {10, 11}, {11, 11}})
+	game.GoTo(20)
+
+	// Going back to before the edit leaves it behind.  Once the blinker is
+	// seen to cycle, GoTo skips ahead without stepping, so it's the
+	// keyframes that must forget the edit.
+	game.GoTo(5)
+	game.Next()
+	game.Next()
+	for _, generation := range []int{31, 17, 25, 10, 13} {
+		if err := game.GoTo(generation); err != nil {
+			t.Fatal(err)
+		}
+		if match, errmsg := samePop(popAt(popFromCells(testPattern), generation), game.Population); !match {
+			t.Errorf("Wrong population at generation %d: %s", generation, errmsg)
+		}
+	}
+}
+
+func TestGoToSkipsCycles(t *testing.T) {
+	game := golife.NewGame()
+	game.AddCells(testPattern)
+	game.SetCycleDetection(4)
+	game.SetHistorySize(0)
+	game.Next()
+	game.Next()
+	if err := game.GoTo(1_000_000_001); err != nil {
+		t.Fatal(err)
+	}
+	if match, errmsg := samePop(popFromCells(testPatternStep), game.Population); !match {
+		t.Errorf("Blinker in wrong phase: %s", errmsg)
+	}
+}
+
+// timelineModel is the simplest possible record of a game: every
+// generation of the current branch, with anything after an edit or a move
+// forward from an earlier generation thrown away.
+type timelineModel struct {
+	pops    []golife.Population
+	current int
+}
+
+func (model *timelineModel) truncate() {
+	model.pops = model.pops[:model.current+1]
+}
+
+func (model *timelineModel) goTo(generation int) {
+	if generation > model.current {
+		model.truncate()
+		for len(model.pops) <= generation {
+			model.pops = append(model.pops, model.pops[len(model.pops)-1].Step())
+		}
+	}
+	model.current = generation
+}
+
+func TestTimelineAgainstModel(t *testing.T) {
+	for seed := range int64(400) {
+		rng := rand.New(rand.NewSource(seed))
+		game := golife.NewGame()
+		game.AddCells(golife.CellList{{0, 0}, {1, 0}, {2, 0}, {1, 2}, {2, 3}, {0, 4}})
+		game.SetHistorySize([]int{-1, 4}[seed/2%2])
+		if seed%2 == 1 {
+			game.SetKeyframeInterval(5)
+		}
+		game.SetCycleDetection(4)
+		game.SetTimeline(3, 5)
+		if seed%3 == 0 {
+			game.EnableJournal(-1)
+		}
+		model := timelineModel{[]golife.Population{popFromCells(slices.Collect(game.Population.All()))}, 0}
+
+		for op := range 60 {
+			var what string
+			switch rng.Intn(6) {
+			case 0, 1:
+				what = "Next"
+				game.Next()
+				model.goTo(model.current + 1)
+			case 2:
+				cell := golife.Cell{golife.Coord(rng.Intn(6)), golife.Coord(rng.Intn(6))}
+				what = fmt.Sprintf("toggle %v", cell)
+				model.truncate()
+				edited := popFromCells(slices.Collect(model.pops[model.current].All()))
+				if game.HasCell(cell) {
+					game.RemoveCell(cell)
+					delete(edited, cell)
+				} else {
+					game.AddCell(cell)
+					edited[cell] = true
+				}
+				model.pops[model.current] = edited
+			case 3:
+				what = "Previous"
+				if game.Previous() == nil {
+					model.current -= 1
+				}
+			case 4:
+				generation := rng.Intn(model.current + 20)
+				what = fmt.Sprintf("SeekTo %d", generation)
+				if game.SeekTo(generation) == nil {
+					model.goTo(generation)
+				}
+			case 5:
+				generation := rng.Intn(model.current + 40)
+				what = fmt.Sprintf("GoTo %d", generation)
+				if err := game.GoTo(generation); err != nil {
+					t.Fatalf("Seed %d op %d: %s failed: %v", seed, op, what, err)
+				}
+				model.goTo(generation)
+			}
+			if game.Generation != model.current {
+				t.Fatalf("Seed %d op %d: at generation %d after %s, expected %d", seed, op, game.Generation, what, model.current)
+			}
+			if match, errmsg := samePop(model.pops[model.current], game.Population); !match {
+				t.Fatalf("Seed %d op %d: wrong population at generation %d after %s: %s", seed, op, game.Generation, what, errmsg)
+			}
+		}
+	}
+}